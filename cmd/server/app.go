@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/120m4n/GridFlow-Dynamics/internal/api/apierror"
+	"github.com/120m4n/GridFlow-Dynamics/internal/config"
+)
+
+// newApp crea la aplicación Fiber con los timeouts configurados y el sobre de
+// error de la API.
+func newApp(server config.ServerConfig) *fiber.App {
+	return fiber.New(fiber.Config{
+		ReadTimeout:  server.ReadTimeout,
+		WriteTimeout: server.WriteTimeout,
+		IdleTimeout:  server.IdleTimeout,
+		ErrorHandler: apierror.ErrorHandler,
+	})
+}
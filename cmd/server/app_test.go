@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/120m4n/GridFlow-Dynamics/internal/config"
+)
+
+func TestNewAppTimeouts(t *testing.T) {
+	app := newApp(config.ServerConfig{ReadTimeout: 7 * time.Second, WriteTimeout: 0, IdleTimeout: 90 * time.Second})
+
+	got := app.Config()
+	if got.ReadTimeout != 7*time.Second || got.WriteTimeout != 0 || got.IdleTimeout != 90*time.Second {
+		t.Errorf("timeouts = %s/%s/%s; want 7s/0s/1m30s", got.ReadTimeout, got.WriteTimeout, got.IdleTimeout)
+	}
+}
+
+func TestNewAppSlowUpload(t *testing.T) {
+	tests := []struct {
+		name        string
+		readTimeout time.Duration
+		wantOK      bool
+	}{
+		{name: "upload slower than the read timeout", readTimeout: 200 * time.Millisecond, wantOK: false},
+		{name: "upload within a raised read timeout", readTimeout: 5 * time.Second, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newApp(config.ServerConfig{ReadTimeout: tt.readTimeout, IdleTimeout: time.Minute})
+			app.Post("/upload", func(c *fiber.Ctx) error {
+				return c.SendString(fmt.Sprint(len(c.Body())))
+			})
+
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("net.Listen error: %v", err)
+			}
+			go app.Listener(ln)
+			t.Cleanup(func() { app.Shutdown() })
+
+			ok := slowUpload(t, ln.Addr().String(), 3, 250*time.Millisecond)
+			if ok != tt.wantOK {
+				t.Errorf("upload succeeded = %v; want %v", ok, tt.wantOK)
+			}
+		})
+	}
+}
+
+// slowUpload sends a POST whose body arrives in chunks separated by pause and
+// reports whether the server answered 200 with the full body length.
+func slowUpload(t *testing.T, addr string, chunks int, pause time.Duration) bool {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("net.Dial error: %v", err)
+	}
+	defer conn.Close()
+
+	chunk := strings.Repeat("x", 1024)
+	fmt.Fprintf(conn, "POST /upload HTTP/1.1\r\nHost: %s\r\nContent-Length: %d\r\n\r\n", addr, chunks*len(chunk))
+	for i := 0; i < chunks; i++ {
+		time.Sleep(pause)
+		if _, err := conn.Write([]byte(chunk)); err != nil {
+			return false
+		}
+	}
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return err == nil && resp.StatusCode == http.StatusOK && string(body) == fmt.Sprint(chunks*len(chunk))
+}
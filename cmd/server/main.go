@@ -2,91 +2,307 @@
 package main
 
 import (
-	"fmt"
-	"log"
+	"context"
+	"crypto/tls"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/nats-io/nats.go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	"github.com/120m4n/GridFlow-Dynamics/internal/api/handlers"
 	"github.com/120m4n/GridFlow-Dynamics/internal/api/middleware"
+	"github.com/120m4n/GridFlow-Dynamics/internal/archive"
 	"github.com/120m4n/GridFlow-Dynamics/internal/config"
+	"github.com/120m4n/GridFlow-Dynamics/internal/grpcapi"
+	"github.com/120m4n/GridFlow-Dynamics/internal/ingest"
+	"github.com/120m4n/GridFlow-Dynamics/internal/lifecycle"
+	"github.com/120m4n/GridFlow-Dynamics/internal/logging"
 	"github.com/120m4n/GridFlow-Dynamics/internal/messaging"
+	"github.com/120m4n/GridFlow-Dynamics/internal/mqtt"
+	"github.com/120m4n/GridFlow-Dynamics/internal/ratelimit"
+	"github.com/120m4n/GridFlow-Dynamics/internal/tracing"
+	"github.com/120m4n/GridFlow-Dynamics/internal/version"
 )
 
 func main() {
-	log.Println("Iniciando GridFlow-Dynamics Platform...")
-
 	// Cargar configuración
-	cfg := config.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		fatal(slog.Default(), "Fallo al cargar configuración", "error", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		fatal(slog.Default(), "Configuración inválida", "error", err)
+	}
 
-	// Crear conexión NATS
-	conn := messaging.NewConnection(cfg.NATS.URL)
-	if err := conn.Connect(); err != nil {
-		log.Printf("Advertencia: No se pudo conectar a NATS: %v", err)
-		log.Println("La plataforma funcionará en modo standalone sin mensajería")
-	} else {
-		log.Println("Conectado a NATS exitosamente")
-		defer conn.Close()
+	// Configurar logger estructurado
+	logLevel := new(slog.LevelVar)
+	level, _ := logging.ParseLevel(cfg.Log.Level)
+	logLevel.Set(level)
+	logger, err := logging.New(os.Stdout, logLevel, cfg.Log.Format)
+	if err != nil {
+		fatal(slog.Default(), "Fallo al configurar logger", "error", err)
+	}
+	slog.SetDefault(logger)
+
+	logger.Info("Iniciando GridFlow-Dynamics Platform...", version.LogAttrs()...)
+	prometheus.MustRegister(version.NewBuildInfoCollector())
+
+	// Configurar trazas OpenTelemetry (no-op sin OTEL_EXPORTER_OTLP_ENDPOINT)
+	shutdownTracing, err := tracing.Setup(context.Background(), "gridflow-api")
+	if err != nil {
+		fatal(logger, "Fallo al configurar trazas", "error", err)
 	}
 
-	// Crear publisher para handlers de API
-	var publisher *messaging.Publisher
-	if conn.IsConnected() {
-		var err error
-		publisher, err = messaging.NewPublisher(conn)
+	// Cargar certificados TLS antes de conectar para fallar rápido
+	tlsConfig, err := cfg.Server.TLSConfig()
+	if err != nil {
+		fatal(logger, "Configuración TLS inválida", "error", err)
+	}
+
+	// Conectar a NATS en segundo plano; el publisher queda disponible al conectar
+	messagingLogger := logging.Component(logger, "messaging")
+	conn := messaging.NewConnection(cfg.NATS.URL, messagingLogger)
+	natsSupervisor := messaging.NewSupervisor(conn, messagingLogger).
+		WithPublisherOptions(messaging.WithRetry(cfg.NATS.PublishRetries, cfg.NATS.PublishRetryBackoff))
+	publishStats := messaging.NewPublishStats()
+	prometheus.MustRegister(publishStats)
+	natsSupervisor.WithPublisherOptions(messaging.WithStats(publishStats))
+	if cfg.NATS.BreakerThreshold > 0 {
+		breaker := messaging.NewBreaker(cfg.NATS.BreakerThreshold, cfg.NATS.BreakerCooldown)
+		prometheus.MustRegister(breaker)
+		natsSupervisor.WithPublisherOptions(messaging.WithBreaker(breaker))
+	}
+	if cfg.NATS.CloudEvents {
+		natsSupervisor.WithPublisherOptions(messaging.WithCloudEvents(messaging.SourceAPI, map[string]string{
+			cfg.NATS.InventarioSubject(): messaging.TypeInventarioCuadrilla,
+		}))
+	}
+
+	// Archivo opcional de eventos crudos en S3; se suscribe al conectar a NATS
+	var archiver *archive.Archiver
+	if cfg.Archive.Enabled() {
+		store, err := archive.NewS3Store(archive.S3Options{
+			Endpoint:  cfg.Archive.Endpoint,
+			Bucket:    cfg.Archive.Bucket,
+			Region:    cfg.Archive.Region,
+			AccessKey: cfg.Archive.AccessKey,
+			SecretKey: cfg.Archive.SecretKey,
+			UseSSL:    cfg.Archive.UseSSL,
+		})
 		if err != nil {
-			log.Fatalf("Fallo al crear publisher: %v", err)
+			fatal(logger, "Configuración de archivo inválida", "error", err)
 		}
-		defer publisher.Close()
+		archiveLogger := logging.Component(logger, "archive")
+		archiver = archive.NewArchiver(store, archive.Options{
+			Prefix:   cfg.Archive.Prefix,
+			MaxBytes: cfg.Archive.MaxBytes,
+			MaxAge:   cfg.Archive.MaxAge,
+			SpoolDir: cfg.Archive.SpoolDir,
+		}, archiveLogger)
+		archiver.Start()
+		natsSupervisor.OnConnect(func(nc *nats.Conn) {
+			if err := archiver.Subscribe(nc, cfg.NATS.InventarioSubject()); err != nil {
+				archiveLogger.Error("Archivador sin suscripción", "error", err)
+			}
+		})
+		logger.Info("Archivo de eventos habilitado", "endpoint", cfg.Archive.Endpoint, "bucket", cfg.Archive.Bucket)
+	}
+
+	natsSupervisor.Start()
+
+	// Rate limit por cuadrilla compartido por HTTP, gRPC y MQTT
+	rateLimiter := ratelimit.New(cfg.API.RateLimitPerMin, time.Minute)
+
+	// Puente MQTT opcional para rastreadores IoT; comparte validación, rate
+	// limit y publicación con el endpoint HTTP
+	var mqttBridge *mqtt.Bridge
+	if cfg.MQTT.Enabled() {
+		mqttTLS, err := cfg.MQTT.TLSConfig()
+		if err != nil {
+			fatal(logger, "Configuración TLS de MQTT inválida", "error", err)
+		}
+		mqttBridge = mqtt.NewBridge(mqtt.Options{
+			URL:       cfg.MQTT.URL,
+			Topic:     cfg.MQTT.Topic,
+			ClientID:  cfg.MQTT.ClientID,
+			Username:  cfg.MQTT.Username,
+			Password:  cfg.MQTT.Password,
+			TLSConfig: mqttTLS,
+		}, ingest.NewService(natsSupervisor, rateLimiter).WithSubject(cfg.NATS.InventarioSubject()), logging.Component(logger, "mqtt"))
+		mqttBridge.Start()
+		logger.Info("Puente MQTT habilitado", "broker", cfg.MQTT.URL, "topic", cfg.MQTT.Topic)
 	}
 
 	// Configurar aplicación Fiber
-	app := fiber.New(fiber.Config{
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
-	})
+	app := newApp(cfg.Server)
+
+	app.Use(requestid.New())
+	app.Use(middleware.Tracing())
 
 	// Crear middleware
-	rateLimiter := middleware.NewRateLimiter(cfg.API.RateLimitPerMin, time.Minute)
 	hmacValidator := middleware.NewHMACValidator(cfg.API.HMACSecret)
 
 	// Crear handler de inventario
-	inventarioHandler := handlers.NewInventarioHandler(publisher, rateLimiter, hmacValidator)
-	app.Post("/api/v1/mensaje_inventario/cuadrilla", inventarioHandler.Handle)
+	inventarioHandler := handlers.NewInventarioHandler(natsSupervisor, rateLimiter).
+		WithSubject(cfg.NATS.InventarioSubject()).
+		WithLogger(logging.Component(logger, "handler"))
+	app.Post("/api/v1/mensaje_inventario/cuadrilla", middleware.RequireSignature(hmacValidator), inventarioHandler.Handle)
+
+	// Servidor gRPC opcional para telemetría de alta frecuencia; comparte
+	// validación, rate limit y publicación con el endpoint HTTP
+	var grpcServer *grpc.Server
+	if cfg.GRPC.Enabled() {
+		var opts []grpc.ServerOption
+		if tlsConfig != nil {
+			opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+		}
+		service := ingest.NewService(natsSupervisor, rateLimiter).WithSubject(cfg.NATS.InventarioSubject())
+		grpcServer = grpcapi.NewServer(service, grpcapi.NewAuthenticator(cfg.GRPC.CrewTokens), logging.Component(logger, "grpc"), opts...)
+
+		grpcAddr := net.JoinHostPort(cfg.Server.ListenAddress, cfg.GRPC.Port)
+		ln, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			fatal(logger, "Fallo al escuchar", "addr", grpcAddr, "error", err)
+		}
+		go func() {
+			logger.Info("Iniciando servidor gRPC", "addr", grpcAddr)
+			if err := grpcServer.Serve(ln); err != nil {
+				fatal(logger, "Servidor gRPC falló", "error", err)
+			}
+		}()
+	}
 
 	// Endpoint de salud
 	app.Get("/health", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{"status": "healthy"})
 	})
 
+	// Información de compilación y métricas
+	app.Get("/version", handlers.Version)
+	app.Get("/metrics", adaptor.HTTPHandler(promhttp.Handler()))
+
+	// Perfiles pprof y diagnóstico en tiempo de ejecución (solo con DEBUG_ENDPOINTS=true)
+	handlers.MountDebug(app, cfg.Admin.DebugEndpoints, cfg.Admin.Token)
+	if cfg.Admin.DebugEndpoints {
+		logger.Warn("Endpoints de depuración habilitados en /debug")
+	}
+
+	// Endpoint de disponibilidad: degradado mientras no hay conexión a NATS
+	app.Get("/ready", func(c *fiber.Ctx) error {
+		if !natsSupervisor.Ready() {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"status": "degraded", "nats": "disconnected"})
+		}
+		return c.JSON(fiber.Map{"status": "ready", "nats": "connected"})
+	})
+
 	// Iniciar servidor HTTP en una goroutine
-	go func() {
-		addr := fmt.Sprintf(":%s", cfg.Server.Port)
-		log.Printf("Iniciando servidor HTTP en puerto %s", cfg.Server.Port)
-		if err := app.Listen(addr); err != nil {
-			log.Fatalf("Servidor HTTP falló: %v", err)
+	addr := cfg.Server.Addr()
+	var redirectServer *http.Server
+	if tlsConfig != nil {
+		ln, err := tls.Listen("tcp", addr, tlsConfig)
+		if err != nil {
+			fatal(logger, "Fallo al escuchar", "addr", addr, "error", err)
+		}
+		go func() {
+			logger.Info("Iniciando servidor HTTPS", "addr", addr)
+			if err := app.Listener(ln); err != nil {
+				fatal(logger, "Servidor HTTPS falló", "error", err)
+			}
+		}()
+
+		if cfg.Server.HTTPRedirectPort != "" {
+			redirectServer = newHTTPSRedirect(cfg.Server)
+			go func() {
+				logger.Info("Redirigiendo HTTP hacia HTTPS", "addr", redirectServer.Addr)
+				if err := redirectServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					logger.Error("Servidor de redirección HTTP falló", "error", err)
+				}
+			}()
 		}
-	}()
+	} else {
+		go func() {
+			logger.Info("Iniciando servidor HTTP", "addr", addr)
+			if err := app.Listen(addr); err != nil {
+				fatal(logger, "Servidor HTTP falló", "error", err)
+			}
+		}()
+	}
 
-	log.Println("GridFlow-Dynamics Platform está corriendo")
-	log.Printf("Configurado para soportar 200 cuadrillas simultáneas")
-	log.Printf("Endpoint de inventario: POST /api/v1/mensaje_inventario/cuadrilla")
-	log.Printf("Rate limit: %d requests/minuto por cuadrilla", cfg.API.RateLimitPerMin)
+	logger.Info("GridFlow-Dynamics Platform está corriendo",
+		"cuadrillas_soportadas", 200,
+		"endpoint", "POST /api/v1/mensaje_inventario/cuadrilla",
+		"subject", cfg.NATS.InventarioSubject(),
+		"rate_limit_por_minuto", cfg.API.RateLimitPerMin,
+		"log_level", cfg.Log.Level)
+
+	// Recargar configuración en caliente con SIGHUP
+	reloadChan := make(chan os.Signal, 1)
+	signal.Notify(reloadChan, syscall.SIGHUP)
+	go func(current *config.Config) {
+		for range reloadChan {
+			current = reloadConfig(current, config.Load, rateLimiter, logLevel, logger)
+		}
+	}(cfg)
 
 	// Esperar señal de apagado
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 	<-sigChan
 
-	log.Println("Apagando GridFlow-Dynamics Platform...")
+	logger.Info("Apagando GridFlow-Dynamics Platform...")
+
+	// Apagado ordenado: primero dejar de aceptar solicitudes, luego vaciar
+	// publicaciones pendientes y por último cerrar conexiones y exportadores
+	shutdown := lifecycle.NewManager(logging.Component(logger, "lifecycle"))
+	shutdown.Register("servidor HTTP", 10*time.Second, app.ShutdownWithContext)
+	if redirectServer != nil {
+		shutdown.Register("redirección HTTP", 5*time.Second, redirectServer.Shutdown)
+	}
+	if grpcServer != nil {
+		shutdown.Register("servidor gRPC", 10*time.Second, func(ctx context.Context) error {
+			return grpcapi.Shutdown(ctx, grpcServer)
+		})
+	}
+	if mqttBridge != nil {
+		shutdown.Register("puente MQTT", 5*time.Second, mqttBridge.Stop)
+	}
+	shutdown.Register("publisher NATS", 5*time.Second, func(ctx context.Context) error {
+		if publisher := natsSupervisor.Publisher(); publisher != nil && natsSupervisor.Ready() {
+			return publisher.Flush(ctx)
+		}
+		return nil
+	})
+	if archiver != nil {
+		shutdown.Register("archivador", 30*time.Second, archiver.Stop)
+	}
+	shutdown.Register("conexión NATS", 5*time.Second, func(ctx context.Context) error {
+		natsSupervisor.Stop()
+		return nil
+	})
+	shutdown.Register("exportador de trazas", 5*time.Second, shutdownTracing)
 
-	// Apagado graceful del servidor HTTP
-	if err := app.ShutdownWithTimeout(10 * time.Second); err != nil {
-		log.Printf("Error al apagar servidor HTTP: %v", err)
+	if err := shutdown.Shutdown(); err != nil {
+		logger.Error("Apagado incompleto", "error", err)
+		os.Exit(1)
 	}
+	logger.Info("GridFlow-Dynamics Platform detenida")
+}
+
+// fatal registra el error y termina el proceso.
+func fatal(logger *slog.Logger, msg string, args ...any) {
+	logger.Error(msg, args...)
+	os.Exit(1)
 }
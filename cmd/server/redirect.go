@@ -0,0 +1,39 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/120m4n/GridFlow-Dynamics/internal/config"
+)
+
+// newHTTPSRedirect crea el servidor HTTP plano del puerto de redirección, que
+// envía cada solicitud a la misma ruta sobre HTTPS. El llamador lo inicia y
+// registra su Shutdown.
+func newHTTPSRedirect(server config.ServerConfig) *http.Server {
+	return &http.Server{
+		Addr:              net.JoinHostPort(server.ListenAddress, server.HTTPRedirectPort),
+		Handler:           httpsRedirectHandler(server.Port),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+}
+
+// httpsRedirectHandler redirige al mismo host en el puerto HTTPS port,
+// omitiendo el puerto cuando es el 443.
+func httpsRedirectHandler(port string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			// Host sin puerto; una IPv6 llega entre corchetes
+			host = strings.TrimSuffix(strings.TrimPrefix(r.Host, "["), "]")
+		}
+		target := url.URL{Scheme: "https", Host: net.JoinHostPort(host, port), Path: r.URL.Path, RawQuery: r.URL.RawQuery}
+		if port == "443" {
+			target.Host = strings.TrimSuffix(target.Host, ":443")
+		}
+		http.Redirect(w, r, target.String(), http.StatusPermanentRedirect)
+	})
+}
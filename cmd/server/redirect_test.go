@@ -0,0 +1,39 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPSRedirectHandler(t *testing.T) {
+	tests := []struct {
+		name string
+		host string
+		port string
+		want string
+	}{
+		{name: "host with port", host: "example.com:8080", port: "8443", want: "https://example.com:8443/api?x=1"},
+		{name: "host without port", host: "example.com", port: "8443", want: "https://example.com:8443/api?x=1"},
+		{name: "default https port", host: "example.com:80", port: "443", want: "https://example.com/api?x=1"},
+		{name: "ipv6 with port", host: "[::1]:8080", port: "8443", want: "https://[::1]:8443/api?x=1"},
+		{name: "ipv6 without port", host: "[::1]", port: "8443", want: "https://[::1]:8443/api?x=1"},
+		{name: "ipv6 default https port", host: "[::1]", port: "443", want: "https://[::1]/api?x=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api?x=1", nil)
+			req.Host = tt.host
+			rec := httptest.NewRecorder()
+			httpsRedirectHandler(tt.port).ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusPermanentRedirect {
+				t.Errorf("status = %d; want %d", rec.Code, http.StatusPermanentRedirect)
+			}
+			if got := rec.Header().Get("Location"); got != tt.want {
+				t.Errorf("Location = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/120m4n/GridFlow-Dynamics/internal/config"
+	"github.com/120m4n/GridFlow-Dynamics/internal/logging"
+	"github.com/120m4n/GridFlow-Dynamics/internal/ratelimit"
+)
+
+// reloadConfig vuelve a cargar la configuración con load y aplica los valores
+// que pueden cambiar sin reiniciar. Retorna la configuración vigente tras la
+// recarga.
+func reloadConfig(current *config.Config, load func() (*config.Config, error), rateLimiter *ratelimit.Limiter, logLevel *slog.LevelVar, logger *slog.Logger) *config.Config {
+	logger.Info("Recargando configuración...")
+
+	next, err := load()
+	if err != nil {
+		logger.Error("Recarga de configuración fallida", "error", err)
+		return current
+	}
+	if err := next.Validate(); err != nil {
+		logger.Error("Recarga de configuración rechazada", "error", err)
+		return current
+	}
+
+	if fields := current.RestartRequired(next); len(fields) > 0 {
+		logger.Warn("Cambios que requieren reiniciar el servidor; se mantienen los valores actuales", "campos", strings.Join(fields, ", "))
+	}
+
+	applied := *current
+	if next.API.RateLimitPerMin != current.API.RateLimitPerMin {
+		rateLimiter.SetLimit(next.API.RateLimitPerMin)
+		applied.API.RateLimitPerMin = next.API.RateLimitPerMin
+		logger.Info("Rate limit actualizado", "anterior", current.API.RateLimitPerMin, "nuevo", next.API.RateLimitPerMin)
+	}
+	if next.Log.Level != current.Log.Level {
+		level, _ := logging.ParseLevel(next.Log.Level)
+		logLevel.Set(level)
+		applied.Log.Level = next.Log.Level
+		logger.Info("Nivel de log actualizado", "anterior", current.Log.Level, "nuevo", next.Log.Level)
+	}
+
+	return &applied
+}
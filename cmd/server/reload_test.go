@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/120m4n/GridFlow-Dynamics/internal/config"
+	"github.com/120m4n/GridFlow-Dynamics/internal/ratelimit"
+)
+
+func TestReloadConfig(t *testing.T) {
+	base, err := config.Load()
+	if err != nil {
+		t.Fatalf("config.Load error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		modify    func(c *config.Config)
+		loadErr   error
+		wantLimit int
+		wantLevel slog.Level
+		wantURL   string
+		wantLog   []string
+	}{
+		{
+			name: "applies rate limit and log level",
+			modify: func(c *config.Config) {
+				c.API.RateLimitPerMin = 5
+				c.Log.Level = "debug"
+			},
+			wantLimit: 5,
+			wantLevel: slog.LevelDebug,
+			wantURL:   base.NATS.URL,
+			wantLog:   []string{"Rate limit actualizado", "Nivel de log actualizado"},
+		},
+		{
+			name: "keeps fields that require a restart",
+			modify: func(c *config.Config) {
+				c.NATS.URL = "nats://otro:4222"
+				c.API.RateLimitPerMin = 7
+			},
+			wantLimit: 7,
+			wantLevel: slog.LevelInfo,
+			wantURL:   base.NATS.URL,
+			wantLog:   []string{"requieren reiniciar", "NATS_"},
+		},
+		{
+			name:      "rejects an invalid configuration",
+			modify:    func(c *config.Config) { c.API.RateLimitPerMin = 0 },
+			wantLimit: base.API.RateLimitPerMin,
+			wantLevel: slog.LevelInfo,
+			wantURL:   base.NATS.URL,
+			wantLog:   []string{"Recarga de configuración rechazada"},
+		},
+		{
+			name:      "keeps the current configuration when loading fails",
+			loadErr:   errors.New("yaml inválido"),
+			wantLimit: base.API.RateLimitPerMin,
+			wantLevel: slog.LevelInfo,
+			wantURL:   base.NATS.URL,
+			wantLog:   []string{"Recarga de configuración fallida"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := *base
+			current.Log.Level = "info"
+			load := func() (*config.Config, error) {
+				if tt.loadErr != nil {
+					return nil, tt.loadErr
+				}
+				next := current
+				tt.modify(&next)
+				return &next, nil
+			}
+
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&logs, nil))
+			rateLimiter := ratelimit.New(current.API.RateLimitPerMin, time.Minute)
+			logLevel := new(slog.LevelVar)
+
+			got := reloadConfig(&current, load, rateLimiter, logLevel, logger)
+
+			if rateLimiter.Limit() != tt.wantLimit || got.API.RateLimitPerMin != tt.wantLimit {
+				t.Errorf("limit = %d (config %d); want %d", rateLimiter.Limit(), got.API.RateLimitPerMin, tt.wantLimit)
+			}
+			if logLevel.Level() != tt.wantLevel {
+				t.Errorf("log level = %v; want %v", logLevel.Level(), tt.wantLevel)
+			}
+			if got.NATS.URL != tt.wantURL {
+				t.Errorf("NATS.URL = %q; want %q", got.NATS.URL, tt.wantURL)
+			}
+			for _, want := range tt.wantLog {
+				if !strings.Contains(logs.String(), want) {
+					t.Errorf("log = %q; want it to mention %q", logs.String(), want)
+				}
+			}
+		})
+	}
+}
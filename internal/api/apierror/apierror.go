@@ -0,0 +1,144 @@
+// Package apierror defines the JSON error envelope returned by the HTTP API
+// and its stable, machine-readable error codes.
+package apierror
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Code identifies an error condition. Codes are part of the API contract:
+// clients branch on them, so existing values must never change meaning.
+type Code string
+
+const (
+	CodeBadRequest       Code = "ERR_BAD_REQUEST"
+	CodeBadSignature     Code = "ERR_BAD_SIGNATURE"
+	CodeInvalidJSON      Code = "ERR_INVALID_JSON"
+	CodeValidation       Code = "ERR_VALIDATION"
+	CodeRateLimited      Code = "ERR_RATE_LIMITED"
+	CodeUnavailable      Code = "ERR_UNAVAILABLE"
+	CodePublishFailed    Code = "ERR_PUBLISH_FAILED"
+	CodeEncodingFailed   Code = "ERR_ENCODING_FAILED"
+	CodeUnauthorized     Code = "ERR_UNAUTHORIZED"
+	CodeNotFound         Code = "ERR_NOT_FOUND"
+	CodeMethodNotAllowed Code = "ERR_METHOD_NOT_ALLOWED"
+	CodeInternal         Code = "ERR_INTERNAL"
+)
+
+// Error is the body of every error response:
+//
+//	{"status":"error","code":"ERR_RATE_LIMITED","message":"...","details":{...},"request_id":"..."}
+type Error struct {
+	HTTPStatus int    `json:"-"`
+	Code       Code   `json:"code"`
+	Message    string `json:"message"`
+	Details    any    `json:"details,omitempty"`
+	RequestID  string `json:"request_id,omitempty"`
+
+	// Retryable tells the client it may resend the same request later.
+	Retryable bool `json:"retryable,omitempty"`
+}
+
+// New creates an error with the given HTTP status, code and message.
+func New(status int, code Code, message string) *Error {
+	return &Error{HTTPStatus: status, Code: code, Message: message}
+}
+
+// WithDetails attaches structured details to the error.
+func (e *Error) WithDetails(details any) *Error {
+	e.Details = details
+	return e
+}
+
+// AsRetryable marks the error as retryable.
+func (e *Error) AsRetryable() *Error {
+	e.Retryable = true
+	return e
+}
+
+func (e *Error) Error() string {
+	return string(e.Code) + ": " + e.Message
+}
+
+// BadSignature reports a missing or invalid request signature.
+func BadSignature(message string) *Error {
+	return New(fiber.StatusUnauthorized, CodeBadSignature, message)
+}
+
+// InvalidJSON reports a body that could not be parsed.
+func InvalidJSON(message string) *Error {
+	return New(fiber.StatusBadRequest, CodeInvalidJSON, message)
+}
+
+// Validation reports a payload that failed domain validation.
+func Validation(message string) *Error {
+	return New(fiber.StatusBadRequest, CodeValidation, message)
+}
+
+// RateLimited reports that the caller exceeded its request quota.
+func RateLimited(message string) *Error {
+	return New(fiber.StatusTooManyRequests, CodeRateLimited, message)
+}
+
+// Unavailable reports that a dependency is down and the request may be retried.
+func Unavailable(message string) *Error {
+	return New(fiber.StatusServiceUnavailable, CodeUnavailable, message).AsRetryable()
+}
+
+// PublishFailed reports that a valid event could not be published.
+func PublishFailed(message string) *Error {
+	return New(fiber.StatusInternalServerError, CodePublishFailed, message)
+}
+
+// EncodingFailed reports that a valid event could not be serialized for
+// publishing. Resending the same request will fail again.
+func EncodingFailed(message string) *Error {
+	return New(fiber.StatusInternalServerError, CodeEncodingFailed, message)
+}
+
+// Unauthorized reports missing or invalid credentials.
+func Unauthorized(message string) *Error {
+	return New(fiber.StatusUnauthorized, CodeUnauthorized, message)
+}
+
+// Send writes e as the response, filling in the request ID set by the
+// requestid middleware when present.
+func Send(c *fiber.Ctx, e *Error) error {
+	if e.RequestID == "" {
+		e.RequestID = c.GetRespHeader(fiber.HeaderXRequestID)
+	}
+	return c.Status(e.HTTPStatus).JSON(envelope{Status: "error", Error: e})
+}
+
+// envelope adds the "status" field shared with success responses.
+type envelope struct {
+	Status string `json:"status"`
+	*Error
+}
+
+// ErrorHandler is a fiber.Config.ErrorHandler that renders errors returned by
+// handlers, including Fiber's own 404 and 405, with the error envelope.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return Send(c, apiErr)
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		switch fiberErr.Code {
+		case fiber.StatusNotFound:
+			return Send(c, New(fiberErr.Code, CodeNotFound, "Ruta no encontrada"))
+		case fiber.StatusMethodNotAllowed:
+			return Send(c, New(fiberErr.Code, CodeMethodNotAllowed, "Método no permitido"))
+		case fiber.StatusUnauthorized:
+			return Send(c, New(fiberErr.Code, CodeUnauthorized, fiberErr.Message))
+		}
+		if fiberErr.Code < fiber.StatusInternalServerError {
+			return Send(c, New(fiberErr.Code, CodeBadRequest, fiberErr.Message))
+		}
+	}
+	return Send(c, New(fiber.StatusInternalServerError, CodeInternal, "Error interno del servidor"))
+}
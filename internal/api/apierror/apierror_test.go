@@ -0,0 +1,99 @@
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+)
+
+type body struct {
+	Status    string `json:"status"`
+	Code      Code   `json:"code"`
+	Message   string `json:"message"`
+	Details   any    `json:"details"`
+	RequestID string `json:"request_id"`
+	Retryable bool   `json:"retryable"`
+}
+
+func do(t *testing.T, app *fiber.App, method, path string) (*http.Response, body) {
+	t.Helper()
+	resp, err := app.Test(httptest.NewRequest(method, path, nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	var b body
+	if err := json.NewDecoder(resp.Body).Decode(&b); err != nil {
+		t.Fatalf("invalid error body: %v", err)
+	}
+	return resp, b
+}
+
+func TestSend(t *testing.T) {
+	app := fiber.New()
+	app.Use(requestid.New())
+	app.Get("/limited", func(c *fiber.Ctx) error {
+		return Send(c, RateLimited("too many").WithDetails(fiber.Map{"limit": 2}))
+	})
+
+	resp, b := do(t, app, "GET", "/limited")
+	if resp.StatusCode != fiber.StatusTooManyRequests {
+		t.Errorf("status = %d; want %d", resp.StatusCode, fiber.StatusTooManyRequests)
+	}
+	if b.Status != "error" || b.Code != CodeRateLimited || b.Message != "too many" {
+		t.Errorf("body = %+v; want rate limit envelope", b)
+	}
+	if details, ok := b.Details.(map[string]any); !ok || details["limit"] != float64(2) {
+		t.Errorf("details = %v; want limit 2", b.Details)
+	}
+	if b.RequestID == "" || b.RequestID != resp.Header.Get(fiber.HeaderXRequestID) {
+		t.Errorf("request_id = %q; want the X-Request-ID header %q", b.RequestID, resp.Header.Get(fiber.HeaderXRequestID))
+	}
+	if b.Retryable {
+		t.Error("rate limit errors are not marked retryable")
+	}
+}
+
+func TestUnavailableIsRetryable(t *testing.T) {
+	if e := Unavailable("down"); !e.Retryable || e.HTTPStatus != fiber.StatusServiceUnavailable {
+		t.Errorf("Unavailable = %+v; want retryable 503", e)
+	}
+}
+
+func TestErrorHandler(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Post("/resource", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+	app.Get("/api-error", func(c *fiber.Ctx) error { return Validation("bad field") })
+	app.Get("/bad-request", func(c *fiber.Ctx) error { return fiber.NewError(fiber.StatusRequestEntityTooLarge, "too large") })
+	app.Get("/boom", func(c *fiber.Ctx) error { return errors.New("secret internal detail") })
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantCode   Code
+	}{
+		{name: "unknown route", method: "GET", path: "/missing", wantStatus: fiber.StatusNotFound, wantCode: CodeNotFound},
+		{name: "wrong method", method: "GET", path: "/resource", wantStatus: fiber.StatusMethodNotAllowed, wantCode: CodeMethodNotAllowed},
+		{name: "returned API error", method: "GET", path: "/api-error", wantStatus: fiber.StatusBadRequest, wantCode: CodeValidation},
+		{name: "fiber client error", method: "GET", path: "/bad-request", wantStatus: fiber.StatusRequestEntityTooLarge, wantCode: CodeBadRequest},
+		{name: "unexpected error", method: "GET", path: "/boom", wantStatus: fiber.StatusInternalServerError, wantCode: CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, b := do(t, app, tt.method, tt.path)
+			if resp.StatusCode != tt.wantStatus || b.Code != tt.wantCode {
+				t.Errorf("got %d %s; want %d %s", resp.StatusCode, b.Code, tt.wantStatus, tt.wantCode)
+			}
+			if b.Message == "secret internal detail" {
+				t.Error("internal error text must not leak to clients")
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http/pprof"
+	"runtime"
+	runtimepprof "runtime/pprof"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+
+	"github.com/120m4n/GridFlow-Dynamics/internal/api/middleware"
+)
+
+// MountDebug registra bajo /debug los perfiles pprof, un volcado de goroutines
+// y estadísticas del GC, protegidos por el token de administración. Si enabled
+// es falso no registra ninguna ruta.
+func MountDebug(router fiber.Router, enabled bool, adminToken string) {
+	if !enabled {
+		return
+	}
+
+	debug := router.Group("/debug", middleware.AdminAuth(adminToken))
+
+	debug.Get("/pprof/cmdline", adaptor.HTTPHandlerFunc(pprof.Cmdline))
+	debug.Get("/pprof/profile", adaptor.HTTPHandlerFunc(pprof.Profile))
+	debug.Get("/pprof/symbol", adaptor.HTTPHandlerFunc(pprof.Symbol))
+	debug.Post("/pprof/symbol", adaptor.HTTPHandlerFunc(pprof.Symbol))
+	debug.Get("/pprof/trace", adaptor.HTTPHandlerFunc(pprof.Trace))
+	debug.Get("/pprof/*", adaptor.HTTPHandlerFunc(pprof.Index))
+
+	debug.Get("/goroutines", debugGoroutines)
+	debug.Get("/gc", debugGC)
+}
+
+// debugGoroutines vuelca las pilas de todas las goroutines en texto plano.
+func debugGoroutines(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+	fmt.Fprintf(c, "goroutines: %d\n\n", runtime.NumGoroutine())
+	return runtimepprof.Lookup("goroutine").WriteTo(c, 2)
+}
+
+// debugGC responde con estadísticas de memoria y del recolector de basura.
+func debugGC(c *fiber.Ctx) error {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	var lastGC string
+	if m.LastGC > 0 {
+		lastGC = time.Unix(0, int64(m.LastGC)).UTC().Format(time.RFC3339Nano)
+	}
+
+	return c.JSON(fiber.Map{
+		"goroutines":       runtime.NumGoroutine(),
+		"num_gc":           m.NumGC,
+		"last_gc":          lastGC,
+		"pause_total_ns":   m.PauseTotalNs,
+		"heap_alloc_bytes": m.HeapAlloc,
+		"heap_inuse_bytes": m.HeapInuse,
+		"heap_objects":     m.HeapObjects,
+		"sys_bytes":        m.Sys,
+		"next_gc_bytes":    m.NextGC,
+	})
+}
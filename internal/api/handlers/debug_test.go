@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const tokenAdminPrueba = "admin-secret"
+
+var rutasDebug = []string{"/debug/pprof/", "/debug/pprof/heap", "/debug/goroutines", "/debug/gc"}
+
+func solicitarDebug(t *testing.T, app *fiber.App, ruta, token string) (int, string) {
+	t.Helper()
+	req := httptest.NewRequest("GET", ruta, nil)
+	if token != "" {
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+	}
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("Error en la solicitud %s: %v", ruta, err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func TestMountDebugDeshabilitado(t *testing.T) {
+	app := fiber.New()
+	MountDebug(app, false, tokenAdminPrueba)
+
+	for _, ruta := range rutasDebug {
+		if status, _ := solicitarDebug(t, app, ruta, tokenAdminPrueba); status != fiber.StatusNotFound {
+			t.Errorf("%s: se esperaba 404 con debug deshabilitado, se obtuvo %d", ruta, status)
+		}
+	}
+}
+
+func TestMountDebugSinToken(t *testing.T) {
+	app := fiber.New()
+	MountDebug(app, true, tokenAdminPrueba)
+
+	for _, ruta := range rutasDebug {
+		if status, _ := solicitarDebug(t, app, ruta, ""); status != fiber.StatusUnauthorized {
+			t.Errorf("%s: se esperaba 401 sin token, se obtuvo %d", ruta, status)
+		}
+		if status, _ := solicitarDebug(t, app, ruta, "otro-token"); status != fiber.StatusUnauthorized {
+			t.Errorf("%s: se esperaba 401 con token incorrecto, se obtuvo %d", ruta, status)
+		}
+	}
+}
+
+func TestMountDebugConToken(t *testing.T) {
+	app := fiber.New()
+	MountDebug(app, true, tokenAdminPrueba)
+
+	for _, ruta := range rutasDebug {
+		if status, _ := solicitarDebug(t, app, ruta, tokenAdminPrueba); status != fiber.StatusOK {
+			t.Errorf("%s: se esperaba 200 con token, se obtuvo %d", ruta, status)
+		}
+	}
+
+	_, body := solicitarDebug(t, app, "/debug/goroutines", tokenAdminPrueba)
+	if !strings.Contains(body, "goroutine") {
+		t.Errorf("El volcado de goroutines no contiene pilas: %q", body)
+	}
+
+	_, body = solicitarDebug(t, app, "/debug/gc", tokenAdminPrueba)
+	if !strings.Contains(body, `"num_gc"`) {
+		t.Errorf("Las estadísticas de GC no contienen num_gc: %q", body)
+	}
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/120m4n/GridFlow-Dynamics/internal/api/middleware"
+	"github.com/120m4n/GridFlow-Dynamics/internal/messaging"
+	"github.com/120m4n/GridFlow-Dynamics/internal/messaging/natstest"
+	"github.com/120m4n/GridFlow-Dynamics/internal/ratelimit"
+	"github.com/120m4n/GridFlow-Dynamics/pkg/domain"
+)
+
+func TestInventarioHandlerTrazaConectada(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+
+	publisher, sub := natstest.Subscribed(t, messaging.SubjectInventarioCuadrilla)
+
+	hmacValidator := middleware.NewHMACValidator("test-secret")
+	handler := NewInventarioHandler(publisher, ratelimit.New(100, time.Minute))
+
+	app := fiber.New()
+	app.Use(middleware.Tracing())
+	app.Post("/test", middleware.RequireSignature(hmacValidator), handler.Handle)
+
+	bodyBytes, _ := json.Marshal(domain.MensajeInventarioCuadrilla{
+		GrupoTrabajo:       "G0/TEST",
+		NombreEmpleado:     "Juan Perez",
+		Timestamp:          time.Now(),
+		Coordenadas:        domain.Coordenadas{Latitud: 40.0, Longitud: -74.0},
+		CodigoODT:          "ODT-001",
+		Estado:             "trabajando",
+		PorcentajeProgreso: 75,
+		NivelBateria:       85,
+	})
+	req := httptest.NewRequest("POST", "/test", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(middleware.SignatureHeader, hmacValidator.ComputeSignature(bodyBytes))
+
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("Error en test: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("StatusCode = %d; esperado %d", resp.StatusCode, fiber.StatusOK)
+	}
+
+	msg, err := sub.NextMsg(2 * time.Second)
+	if err != nil {
+		t.Fatalf("No se recibió el evento: %v", err)
+	}
+
+	var serverSpan, publishSpan sdktrace.ReadOnlySpan
+	for _, s := range recorder.Ended() {
+		switch s.SpanKind() {
+		case trace.SpanKindServer:
+			serverSpan = s
+		case trace.SpanKindProducer:
+			publishSpan = s
+		}
+	}
+	if serverSpan == nil || publishSpan == nil {
+		t.Fatalf("Se esperaban spans de servidor y de publicación, obtenidos: %d", len(recorder.Ended()))
+	}
+
+	if publishSpan.Parent().SpanID() != serverSpan.SpanContext().SpanID() {
+		t.Error("El span de publicación debe ser hijo del span HTTP")
+	}
+
+	consumerCtx := trace.SpanContextFromContext(messaging.ExtractContext(context.Background(), msg))
+	if consumerCtx.TraceID() != serverSpan.SpanContext().TraceID() {
+		t.Errorf("TraceID en el mensaje = %s; esperado %s", consumerCtx.TraceID(), serverSpan.SpanContext().TraceID())
+	}
+}
@@ -2,115 +2,130 @@
 package handlers
 
 import (
-	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
-	"time"
+	"log/slog"
+	"math"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 
+	"github.com/120m4n/GridFlow-Dynamics/internal/api/apierror"
 	"github.com/120m4n/GridFlow-Dynamics/internal/api/middleware"
-	"github.com/120m4n/GridFlow-Dynamics/internal/domain"
+	"github.com/120m4n/GridFlow-Dynamics/internal/ingest"
 	"github.com/120m4n/GridFlow-Dynamics/internal/messaging"
+	"github.com/120m4n/GridFlow-Dynamics/internal/ratelimit"
+	"github.com/120m4n/GridFlow-Dynamics/pkg/domain"
 )
 
 // InventarioHandler maneja las solicitudes de inventario de cuadrilla.
 type InventarioHandler struct {
-	publisher     *messaging.Publisher
-	rateLimiter   *middleware.RateLimiter
-	hmacValidator *middleware.HMACValidator
+	service     *ingest.Service
+	rateLimiter *ratelimit.Limiter
+	logger      *slog.Logger
 }
 
-// NewInventarioHandler crea un nuevo handler de inventario.
-func NewInventarioHandler(publisher *messaging.Publisher, rateLimiter *middleware.RateLimiter, hmacValidator *middleware.HMACValidator) *InventarioHandler {
+// NewInventarioHandler crea un nuevo handler de inventario. El publisher se
+// consulta en cada solicitud, de modo que la conexión a NATS puede
+// establecerse después de iniciar el servidor. La ruta debe montar
+// middleware.RequireSignature: el handler lee el cuerpo verificado y la
+// cuadrilla del contexto de la solicitud.
+func NewInventarioHandler(publishers messaging.PublisherProvider, rateLimiter *ratelimit.Limiter) *InventarioHandler {
 	return &InventarioHandler{
-		publisher:     publisher,
-		rateLimiter:   rateLimiter,
-		hmacValidator: hmacValidator,
+		service:     ingest.NewService(publishers, rateLimiter),
+		rateLimiter: rateLimiter,
+		logger:      slog.Default(),
 	}
 }
 
-// RespuestaAPI representa la respuesta de la API.
+// WithLogger configura el logger del handler.
+func (h *InventarioHandler) WithLogger(logger *slog.Logger) *InventarioHandler {
+	h.logger = logger
+	return h
+}
+
+// WithSubject configura el subject NATS donde se publican los eventos de inventario.
+func (h *InventarioHandler) WithSubject(subject string) *InventarioHandler {
+	h.service.WithSubject(subject)
+	return h
+}
+
+// RespuestaAPI representa la respuesta exitosa de la API. Los errores usan el
+// sobre de apierror.
 type RespuestaAPI struct {
 	Status  string `json:"status"`
 	Message string `json:"message,omitempty"`
-	Error   string `json:"error,omitempty"`
 }
 
+// retryAfterSeconds es el valor de Retry-After cuando NATS no está disponible;
+// con el circuito abierto se usa lo que falta de su espera.
+const retryAfterSeconds = 5
+
 // Handle maneja las solicitudes POST al endpoint de inventario de cuadrilla usando Fiber.
 func (h *InventarioHandler) Handle(c *fiber.Ctx) error {
-	// Validar firma HMAC
-	body := c.Body()
-	signature := c.Get(middleware.SignatureHeader)
-	if !h.hmacValidator.ValidateSignature(body, signature) {
-		return h.sendError(c, fiber.StatusUnauthorized, "Firma HMAC-SHA256 inválida o faltante")
+	// Sin el cuerpo verificado por middleware.RequireSignature no se procesa nada
+	body, ok := middleware.VerifiedBodyFromContext(c.UserContext())
+	if !ok {
+		return apierror.Send(c, apierror.BadSignature("Solicitud sin firma HMAC-SHA256 verificada"))
 	}
+	crew, _ := middleware.CrewFromContext(c.UserContext())
 
 	// Parsear el payload
 	var mensaje domain.MensajeInventarioCuadrilla
-	if err := c.BodyParser(&mensaje); err != nil {
-		return h.sendError(c, fiber.StatusBadRequest, fmt.Sprintf("Payload JSON inválido: %v", err))
+	if err := json.Unmarshal(body, &mensaje); err != nil {
+		return apierror.Send(c, apierror.InvalidJSON(fmt.Sprintf("Payload JSON inválido: %v", err)))
 	}
 
-	// Validar el payload
-	if err := mensaje.Validar(); err != nil {
-		return h.sendError(c, fiber.StatusBadRequest, err.Error())
-	}
-
-	// Verificar límite de tasa
-	if !h.rateLimiter.Allow(mensaje.GrupoTrabajo) {
-		remaining := h.rateLimiter.Remaining(mensaje.GrupoTrabajo)
+	// Validar, limitar por cuadrilla y publicar a NATS
+	err := h.service.Submit(c.UserContext(), crew, &mensaje)
+
+	var validationErr *ingest.ValidationError
+	var publishErr *ingest.PublishError
+	var circuitErr *messaging.CircuitOpenError
+	switch {
+	case errors.As(err, &validationErr):
+		return apierror.Send(c, apierror.Validation(err.Error()))
+	case errors.Is(err, messaging.ErrNotConnected), errors.Is(err, messaging.ErrPublishTimeout), errors.Is(err, messaging.ErrCircuitOpen):
+		h.logger.Warn("Mensaje de inventario rechazado: NATS no disponible", "grupo_trabajo", crew, "error", err)
+		retryAfter := retryAfterSeconds
+		if errors.As(err, &circuitErr) {
+			// Reintentar antes de que termine la espera del circuito solo lo rechazaría de nuevo
+			retryAfter = max(int(math.Ceil(circuitErr.RetryAfter.Seconds())), 1)
+		}
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+		return apierror.Send(c, apierror.Unavailable("Servicio de mensajería no disponible; reintente más tarde"))
+	case errors.Is(err, ingest.ErrRateLimited):
+		remaining := h.rateLimiter.Remaining(crew)
 		c.Set("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
-		return h.sendError(c, fiber.StatusTooManyRequests, "Rate limit excedido (100 req/min)")
+		return apierror.Send(c, apierror.RateLimited(fmt.Sprintf("Rate limit excedido (%d req/min)", h.rateLimiter.Limit())).
+			WithDetails(fiber.Map{"limite_por_minuto": h.rateLimiter.Limit()}))
+	case errors.As(err, &publishErr):
+		h.logger.Error("Fallo al publicar evento de inventario", "grupo_trabajo", crew, "error", publishErr.Err)
+		if errors.Is(err, messaging.ErrMarshal) {
+			return apierror.Send(c, apierror.EncodingFailed("Fallo al serializar el evento de inventario"))
+		}
+		return apierror.Send(c, apierror.PublishFailed("Fallo al procesar mensaje de inventario"))
+	case err != nil:
+		return err
 	}
 
 	// Configurar headers de límite de tasa
-	remaining := h.rateLimiter.Remaining(mensaje.GrupoTrabajo)
+	remaining := h.rateLimiter.Remaining(crew)
 	c.Set("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
-	c.Set("X-RateLimit-Limit", "100")
-
-	// Convertir a evento
-	evento := h.mensajeAEvento(&mensaje)
+	c.Set("X-RateLimit-Limit", fmt.Sprintf("%d", h.rateLimiter.Limit()))
 
-	// Publicar a NATS (si el publisher está disponible)
-	if h.publisher != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-
-		if err := h.publisher.Publish(ctx, messaging.SubjectInventarioCuadrilla, evento); err != nil {
-			log.Printf("Fallo al publicar evento de inventario: %v", err)
-			return h.sendError(c, fiber.StatusInternalServerError, "Fallo al procesar mensaje de inventario")
-		}
-	}
-
-	log.Printf("Mensaje de inventario recibido de cuadrilla %s: empleado=%s, estado=%s, progreso=%d%%, ODT=%s",
-		mensaje.GrupoTrabajo, mensaje.NombreEmpleado, mensaje.Estado, mensaje.PorcentajeProgreso, mensaje.CodigoODT)
+	h.logger.Debug("Mensaje de inventario recibido",
+		"grupo_trabajo", crew,
+		"empleado", mensaje.NombreEmpleado,
+		"estado", mensaje.Estado,
+		"progreso", mensaje.PorcentajeProgreso,
+		"codigo_odt", mensaje.CodigoODT)
 
 	// Enviar respuesta exitosa
 	return h.sendSuccess(c, "Mensaje de inventario de cuadrilla recibido correctamente.")
 }
 
-func (h *InventarioHandler) mensajeAEvento(m *domain.MensajeInventarioCuadrilla) *domain.EventoInventarioCuadrilla {
-	return &domain.EventoInventarioCuadrilla{
-		GrupoTrabajo:       m.GrupoTrabajo,
-		NombreEmpleado:     m.NombreEmpleado,
-		Timestamp:          m.Timestamp,
-		Coordenadas:        m.Coordenadas,
-		CodigoODT:          m.CodigoODT,
-		Estado:             m.Estado,
-		PorcentajeProgreso: m.PorcentajeProgreso,
-		NivelBateria:       m.NivelBateria,
-		RecibidoEn:         time.Now(),
-	}
-}
-
-func (h *InventarioHandler) sendError(c *fiber.Ctx, status int, message string) error {
-	return c.Status(status).JSON(RespuestaAPI{
-		Status: "error",
-		Error:  message,
-	})
-}
-
 func (h *InventarioHandler) sendSuccess(c *fiber.Ctx, message string) error {
 	return c.Status(fiber.StatusOK).JSON(RespuestaAPI{
 		Status:  "success",
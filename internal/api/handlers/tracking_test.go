@@ -3,25 +3,57 @@ package handlers
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"io"
+	"log/slog"
+	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/nats-io/nats-server/v2/server"
+	natsserver "github.com/nats-io/nats-server/v2/test"
 
+	"github.com/120m4n/GridFlow-Dynamics/internal/api/apierror"
 	"github.com/120m4n/GridFlow-Dynamics/internal/api/middleware"
-	"github.com/120m4n/GridFlow-Dynamics/internal/domain"
+	"github.com/120m4n/GridFlow-Dynamics/internal/messaging"
+	"github.com/120m4n/GridFlow-Dynamics/internal/messaging/natstest"
+	"github.com/120m4n/GridFlow-Dynamics/internal/ratelimit"
+	"github.com/120m4n/GridFlow-Dynamics/pkg/domain"
 )
 
+// leerError decodifica el sobre de error de la respuesta.
+func leerError(t *testing.T, resp *http.Response) apierror.Error {
+	t.Helper()
+	var apiErr apierror.Error
+	if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil {
+		t.Fatalf("Respuesta de error inválida: %v", err)
+	}
+	return apiErr
+}
+
+func mensajeValido() domain.MensajeInventarioCuadrilla {
+	return domain.MensajeInventarioCuadrilla{
+		GrupoTrabajo:       "G0/TEST",
+		NombreEmpleado:     "Juan Perez",
+		Timestamp:          time.Now(),
+		Coordenadas:        domain.Coordenadas{Latitud: 40.0, Longitud: -74.0},
+		CodigoODT:          "ODT-001",
+		Estado:             "trabajando",
+		PorcentajeProgreso: 75,
+		NivelBateria:       85,
+	}
+}
+
 func TestInventarioHandlerValidarHMAC(t *testing.T) {
-	rateLimiter := middleware.NewRateLimiter(100, time.Minute)
+	rateLimiter := ratelimit.New(100, time.Minute)
 	hmacValidator := middleware.NewHMACValidator("test-secret")
 
-	handler := NewInventarioHandler(nil, rateLimiter, hmacValidator)
+	handler := NewInventarioHandler(nil, rateLimiter)
 
 	app := fiber.New()
-	app.Post("/test", handler.Handle)
+	app.Post("/test", middleware.RequireSignature(hmacValidator), handler.Handle)
 
 	body := []byte(`{"grupoTrabajo":"G0/TEST"}`)
 	req := httptest.NewRequest("POST", "/test", bytes.NewReader(body))
@@ -35,19 +67,48 @@ func TestInventarioHandlerValidarHMAC(t *testing.T) {
 	if resp.StatusCode != fiber.StatusUnauthorized {
 		t.Errorf("StatusCode = %d; esperado %d", resp.StatusCode, fiber.StatusUnauthorized)
 	}
+	if code := leerError(t, resp).Code; code != apierror.CodeBadSignature {
+		t.Errorf("code = %s; esperado %s", code, apierror.CodeBadSignature)
+	}
+}
+
+func TestInventarioHandlerSinMiddlewareHMAC(t *testing.T) {
+	hmacValidator := middleware.NewHMACValidator("test-secret")
+	handler := NewInventarioHandler(nil, ratelimit.New(100, time.Minute))
+
+	// Una ruta que no monta RequireSignature no procesa el cuerpo aunque venga firmado
+	app := fiber.New()
+	app.Post("/test", handler.Handle)
+
+	body, _ := json.Marshal(mensajeValido())
+	req := httptest.NewRequest("POST", "/test", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(middleware.SignatureHeader, hmacValidator.ComputeSignature(body))
+
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("Error en test: %v", err)
+	}
+
+	if resp.StatusCode != fiber.StatusUnauthorized {
+		t.Errorf("StatusCode = %d; esperado %d", resp.StatusCode, fiber.StatusUnauthorized)
+	}
+	if code := leerError(t, resp).Code; code != apierror.CodeBadSignature {
+		t.Errorf("code = %s; esperado %s", code, apierror.CodeBadSignature)
+	}
 }
 
 func TestInventarioHandlerPayloadInvalido(t *testing.T) {
-	rateLimiter := middleware.NewRateLimiter(100, time.Minute)
+	rateLimiter := ratelimit.New(100, time.Minute)
 	hmacValidator := middleware.NewHMACValidator("test-secret")
 
-	handler := NewInventarioHandler(nil, rateLimiter, hmacValidator)
+	handler := NewInventarioHandler(nil, rateLimiter)
 
 	app := fiber.New()
-	app.Post("/test", handler.Handle)
+	app.Post("/test", middleware.RequireSignature(hmacValidator), handler.Handle)
 
 	body := []byte(`invalid json`)
-	signature := hmacValidator.Sign(body)
+	signature := hmacValidator.ComputeSignature(body)
 
 	req := httptest.NewRequest("POST", "/test", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
@@ -61,16 +122,19 @@ func TestInventarioHandlerPayloadInvalido(t *testing.T) {
 	if resp.StatusCode != fiber.StatusBadRequest {
 		t.Errorf("StatusCode = %d; esperado %d", resp.StatusCode, fiber.StatusBadRequest)
 	}
+	if code := leerError(t, resp).Code; code != apierror.CodeInvalidJSON {
+		t.Errorf("code = %s; esperado %s", code, apierror.CodeInvalidJSON)
+	}
 }
 
 func TestInventarioHandlerValidaciones(t *testing.T) {
-	rateLimiter := middleware.NewRateLimiter(100, time.Minute)
+	rateLimiter := ratelimit.New(100, time.Minute)
 	hmacValidator := middleware.NewHMACValidator("test-secret")
 
-	handler := NewInventarioHandler(nil, rateLimiter, hmacValidator)
+	handler := NewInventarioHandler(nil, rateLimiter)
 
 	app := fiber.New()
-	app.Post("/test", handler.Handle)
+	app.Post("/test", middleware.RequireSignature(hmacValidator), handler.Handle)
 
 	tests := []struct {
 		nombre      string
@@ -124,7 +188,7 @@ func TestInventarioHandlerValidaciones(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.nombre, func(t *testing.T) {
 			bodyBytes, _ := json.Marshal(tt.mensaje)
-			signature := hmacValidator.Sign(bodyBytes)
+			signature := hmacValidator.ComputeSignature(bodyBytes)
 
 			req := httptest.NewRequest("POST", "/test", bytes.NewReader(bodyBytes))
 			req.Header.Set("Content-Type", "application/json")
@@ -138,32 +202,24 @@ func TestInventarioHandlerValidaciones(t *testing.T) {
 			if tt.esperaError && resp.StatusCode != fiber.StatusBadRequest {
 				t.Errorf("Se esperaba error 400, obtuvo %d", resp.StatusCode)
 			}
+			if code := leerError(t, resp).Code; tt.esperaError && code != apierror.CodeValidation {
+				t.Errorf("code = %s; esperado %s", code, apierror.CodeValidation)
+			}
 		})
 	}
 }
 
 func TestInventarioHandlerRateLimit(t *testing.T) {
-	rateLimiter := middleware.NewRateLimiter(2, time.Minute)
+	rateLimiter := ratelimit.New(2, time.Minute)
 	hmacValidator := middleware.NewHMACValidator("test-secret")
 
-	handler := NewInventarioHandler(nil, rateLimiter, hmacValidator)
+	handler := NewInventarioHandler(natstest.Publisher(t), rateLimiter)
 
 	app := fiber.New()
-	app.Post("/test", handler.Handle)
+	app.Post("/test", middleware.RequireSignature(hmacValidator), handler.Handle)
 
-	mensaje := domain.MensajeInventarioCuadrilla{
-		GrupoTrabajo:       "G0/TEST",
-		NombreEmpleado:     "Juan Perez",
-		Timestamp:          time.Now(),
-		Coordenadas:        domain.Coordenadas{Latitud: 40.0, Longitud: -74.0},
-		CodigoODT:          "ODT-001",
-		Estado:             "trabajando",
-		PorcentajeProgreso: 75,
-		NivelBateria:       85,
-	}
-
-	bodyBytes, _ := json.Marshal(mensaje)
-	signature := hmacValidator.Sign(bodyBytes)
+	bodyBytes, _ := json.Marshal(mensajeValido())
+	signature := hmacValidator.ComputeSignature(bodyBytes)
 
 	for i := 0; i < 3; i++ {
 		req := httptest.NewRequest("POST", "/test", bytes.NewReader(bodyBytes))
@@ -173,14 +229,120 @@ func TestInventarioHandlerRateLimit(t *testing.T) {
 		resp, _ := app.Test(req, -1)
 
 		if i < 2 {
-			if resp.StatusCode == fiber.StatusTooManyRequests {
-				t.Errorf("Request %d: no debería estar limitado aún", i+1)
+			if resp.StatusCode != fiber.StatusOK {
+				t.Errorf("Request %d: StatusCode = %d; esperado %d", i+1, resp.StatusCode, fiber.StatusOK)
 			}
 		} else {
 			if resp.StatusCode != fiber.StatusTooManyRequests {
 				body, _ := io.ReadAll(resp.Body)
 				t.Errorf("Request %d: debería estar limitado, obtuvo status %d, body: %s", i+1, resp.StatusCode, string(body))
+			} else if code := leerError(t, resp).Code; code != apierror.CodeRateLimited {
+				t.Errorf("code = %s; esperado %s", code, apierror.CodeRateLimited)
 			}
 		}
 	}
 }
+
+func TestInventarioHandlerSinNATS(t *testing.T) {
+	rateLimiter := ratelimit.New(100, time.Minute)
+	hmacValidator := middleware.NewHMACValidator("test-secret")
+
+	handler := NewInventarioHandler(nil, rateLimiter)
+
+	app := fiber.New()
+	app.Post("/test", middleware.RequireSignature(hmacValidator), handler.Handle)
+
+	bodyBytes, _ := json.Marshal(mensajeValido())
+	req := httptest.NewRequest("POST", "/test", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(middleware.SignatureHeader, hmacValidator.ComputeSignature(bodyBytes))
+
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("Error en test: %v", err)
+	}
+
+	// Sin publisher el mensaje no se acepta, para que el dispositivo lo reintente
+	if resp.StatusCode != fiber.StatusServiceUnavailable {
+		t.Errorf("StatusCode = %d; esperado %d", resp.StatusCode, fiber.StatusServiceUnavailable)
+	}
+	if resp.Header.Get(fiber.HeaderRetryAfter) == "" {
+		t.Error("Se esperaba el header Retry-After")
+	}
+	if apiErr := leerError(t, resp); apiErr.Code != apierror.CodeUnavailable || !apiErr.Retryable {
+		t.Errorf("error = %+v; esperado %s reintentable", apiErr, apierror.CodeUnavailable)
+	}
+}
+
+func TestInventarioHandlerFalloAlPublicar(t *testing.T) {
+	tests := []struct {
+		nombre     string
+		maxPayload int32
+		cerrar     bool
+		abierto    bool
+		wantStatus int
+		wantCode   apierror.Code
+		// wantRetry es el Retry-After esperado en las respuestas 503
+		wantRetry string
+	}{
+		// Un evento mayor al máximo del servidor no se publicará nunca
+		{nombre: "error permanente", maxPayload: 64, wantStatus: fiber.StatusInternalServerError, wantCode: apierror.CodePublishFailed},
+		// El publisher sigue disponible pero su conexión ya no sirve
+		{nombre: "conexión cerrada", cerrar: true, wantStatus: fiber.StatusServiceUnavailable, wantCode: apierror.CodeUnavailable, wantRetry: "5"},
+		// Tras fallos consecutivos el circuito rechaza sin intentar publicar
+		// hasta que termina su espera de un minuto
+		{nombre: "circuito abierto", abierto: true, wantStatus: fiber.StatusServiceUnavailable, wantCode: apierror.CodeUnavailable, wantRetry: "60"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			opts := natsserver.DefaultTestOptions
+			opts.Port = server.RANDOM_PORT
+			opts.MaxPayload = tt.maxPayload
+			srv := natsserver.RunServer(&opts)
+			defer srv.Shutdown()
+
+			conn := messaging.NewConnection(srv.ClientURL(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+			if err := conn.Connect(); err != nil {
+				t.Fatalf("Error al conectar a NATS: %v", err)
+			}
+			defer conn.Close()
+			breaker := messaging.NewBreaker(1, time.Minute)
+			if tt.abierto {
+				breaker.Record(messaging.SubjectInventarioCuadrilla, errors.New("fallo previo"))
+			}
+			publisher, err := messaging.NewPublisher(conn, messaging.WithBreaker(breaker))
+			if err != nil {
+				t.Fatalf("Error al crear publisher: %v", err)
+			}
+			if tt.cerrar {
+				conn.Close()
+			}
+
+			hmacValidator := middleware.NewHMACValidator("test-secret")
+			handler := NewInventarioHandler(publisher, ratelimit.New(100, time.Minute))
+
+			app := fiber.New()
+			app.Post("/test", middleware.RequireSignature(hmacValidator), handler.Handle)
+
+			bodyBytes, _ := json.Marshal(mensajeValido())
+			req := httptest.NewRequest("POST", "/test", bytes.NewReader(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(middleware.SignatureHeader, hmacValidator.ComputeSignature(bodyBytes))
+
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatalf("Error en test: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("StatusCode = %d; esperado %d", resp.StatusCode, tt.wantStatus)
+			}
+			if code := leerError(t, resp).Code; code != tt.wantCode {
+				t.Errorf("code = %s; esperado %s", code, tt.wantCode)
+			}
+			if got := resp.Header.Get(fiber.HeaderRetryAfter); got != tt.wantRetry {
+				t.Errorf("Retry-After = %q; esperado %q", got, tt.wantRetry)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/120m4n/GridFlow-Dynamics/internal/version"
+)
+
+// Version responde con la información de compilación y el tiempo en ejecución.
+func Version(c *fiber.Ctx) error {
+	return c.JSON(version.Get())
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestVersion(t *testing.T) {
+	app := fiber.New()
+	app.Get("/version", Version)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/version", nil))
+	if err != nil {
+		t.Fatalf("Error en la solicitud: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("Se esperaba status 200, se obtuvo %d", resp.StatusCode)
+	}
+
+	var body map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("Respuesta no es JSON: %v", err)
+	}
+	for _, campo := range []string{"version", "commit", "build_date", "go_version", "start_time", "uptime"} {
+		if _, ok := body[campo]; !ok {
+			t.Errorf("Falta el campo %q en la respuesta", campo)
+		}
+	}
+	if body["version"] != "dev" || body["commit"] != "dev" || body["build_date"] != "dev" {
+		t.Errorf("Se esperaban valores dev por defecto, se obtuvo %v", body)
+	}
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/120m4n/GridFlow-Dynamics/internal/api/apierror"
+)
+
+// AdminAuth returns a middleware that requires "Authorization: Bearer <token>".
+// An empty token rejects every request.
+func AdminAuth(token string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		provided, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			return apierror.Send(c, apierror.Unauthorized("Token de administración inválido o faltante"))
+		}
+		return c.Next()
+	}
+}
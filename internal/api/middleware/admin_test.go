@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/120m4n/GridFlow-Dynamics/internal/api/apierror"
+)
+
+func TestAdminAuth(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		want   int
+	}{
+		{name: "valid token", token: "admin-secret", header: "Bearer admin-secret", want: fiber.StatusOK},
+		{name: "missing header", token: "admin-secret", want: fiber.StatusUnauthorized},
+		{name: "wrong token", token: "admin-secret", header: "Bearer nope", want: fiber.StatusUnauthorized},
+		{name: "missing scheme", token: "admin-secret", header: "admin-secret", want: fiber.StatusUnauthorized},
+		{name: "empty configured token", token: "", header: "Bearer ", want: fiber.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/admin", AdminAuth(tt.token), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/admin", nil)
+			if tt.header != "" {
+				req.Header.Set(fiber.HeaderAuthorization, tt.header)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test error: %v", err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d; want %d", resp.StatusCode, tt.want)
+			}
+			if tt.want == fiber.StatusUnauthorized {
+				var apiErr apierror.Error
+				if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil || apiErr.Code != apierror.CodeUnauthorized {
+					t.Errorf("code = %q (err %v); want %s", apiErr.Code, err, apierror.CodeUnauthorized)
+				}
+			}
+		})
+	}
+}
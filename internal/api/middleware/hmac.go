@@ -1,14 +1,28 @@
+// Package middleware provides HTTP middleware for the API.
 package middleware
 
 import (
+	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/120m4n/GridFlow-Dynamics/internal/api/apierror"
+	"github.com/120m4n/GridFlow-Dynamics/pkg/domain"
 )
 
 const (
 	// SignatureHeader is the HTTP header containing the HMAC signature.
-	SignatureHeader = "X-Signature-256"
+	SignatureHeader = domain.SignatureHeader
+
+	// signaturePrefix is the optional algorithm prefix added by webhook
+	// frameworks, as in "sha256=<hex>".
+	signaturePrefix = "sha256="
 )
 
 // HMACValidator validates HMAC-SHA256 signatures on requests.
@@ -24,23 +38,70 @@ func NewHMACValidator(secretKey string) *HMACValidator {
 }
 
 // ValidateSignature validates the HMAC-SHA256 signature of the request body.
+// The signature is hex encoded in either case and may carry a "sha256=" prefix.
 func (v *HMACValidator) ValidateSignature(body []byte, signature string) bool {
-	if signature == "" {
+	signature = strings.TrimPrefix(signature, signaturePrefix)
+	provided, err := hex.DecodeString(signature)
+	if err != nil || len(provided) != sha256.Size {
 		return false
 	}
 
 	// Compute expected signature
 	mac := hmac.New(sha256.New, v.secretKey)
 	mac.Write(body)
-	expectedSignature := hex.EncodeToString(mac.Sum(nil))
 
-	// Constant-time comparison to prevent timing attacks
-	return hmac.Equal([]byte(signature), []byte(expectedSignature))
+	// Constant-time comparison of the raw digests to prevent timing attacks
+	return hmac.Equal(provided, mac.Sum(nil))
 }
 
 // ComputeSignature computes the HMAC-SHA256 signature for the given body.
 func (v *HMACValidator) ComputeSignature(body []byte) string {
-	mac := hmac.New(sha256.New, v.secretKey)
-	mac.Write(body)
-	return hex.EncodeToString(mac.Sum(nil))
+	return domain.ComputeSignature(v.secretKey, body)
+}
+
+// badSignatureMessage is the error message for rejected signatures.
+const badSignatureMessage = "Firma HMAC-SHA256 inválida o faltante"
+
+type (
+	verifiedBodyKey struct{}
+	crewKey         struct{}
+)
+
+// VerifiedBodyFromContext returns the request body verified by
+// RequireSignature.
+func VerifiedBodyFromContext(ctx context.Context) ([]byte, bool) {
+	body, ok := ctx.Value(verifiedBodyKey{}).([]byte)
+	return body, ok
+}
+
+// CrewFromContext returns the crew (grupoTrabajo) of the body verified by
+// RequireSignature. The shared secret does not identify a crew by itself, so
+// this is the crew the signed payload claims; it is empty when the body has
+// none.
+func CrewFromContext(ctx context.Context) (string, bool) {
+	crew, ok := ctx.Value(crewKey{}).(string)
+	return crew, ok
+}
+
+// RequireSignature returns a middleware that rejects requests whose body does
+// not match the SignatureHeader before the route handler runs. Handlers read
+// the verified body and crew from c.UserContext() with
+// VerifiedBodyFromContext and CrewFromContext instead of the request.
+func RequireSignature(v *HMACValidator) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// fasthttp reuses the request buffer, so keep a copy
+		body := bytes.Clone(c.Body())
+		if !v.ValidateSignature(body, c.Get(SignatureHeader)) {
+			return apierror.Send(c, apierror.BadSignature(badSignatureMessage))
+		}
+
+		var claims struct {
+			GrupoTrabajo string `json:"grupoTrabajo"`
+		}
+		json.Unmarshal(body, &claims)
+
+		ctx := context.WithValue(c.UserContext(), verifiedBodyKey{}, body)
+		c.SetUserContext(context.WithValue(ctx, crewKey{}, claims.GrupoTrabajo))
+		return c.Next()
+	}
 }
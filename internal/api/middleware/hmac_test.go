@@ -1,7 +1,16 @@
 package middleware
 
 import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/120m4n/GridFlow-Dynamics/internal/api/apierror"
 )
 
 func TestNewHMACValidator(t *testing.T) {
@@ -66,6 +75,42 @@ func TestHMACValidatorValidateSignature(t *testing.T) {
 			signature: validSig,
 			expected:  false,
 		},
+		{
+			name:      "uppercase hex",
+			body:      body,
+			signature: strings.ToUpper(validSig),
+			expected:  true,
+		},
+		{
+			name:      "sha256 prefix",
+			body:      body,
+			signature: "sha256=" + validSig,
+			expected:  true,
+		},
+		{
+			name:      "prefix only",
+			body:      body,
+			signature: "sha256=",
+			expected:  false,
+		},
+		{
+			name:      "odd length",
+			body:      body,
+			signature: validSig[:len(validSig)-1],
+			expected:  false,
+		},
+		{
+			name:      "non-hex",
+			body:      body,
+			signature: "zz" + validSig[2:],
+			expected:  false,
+		},
+		{
+			name:      "truncated digest",
+			body:      body,
+			signature: validSig[:32],
+			expected:  false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -101,3 +146,102 @@ func TestDifferentSecrets(t *testing.T) {
 		t.Error("Signature should be invalid with different validator")
 	}
 }
+
+func TestRequireSignature(t *testing.T) {
+	v := NewHMACValidator("test-secret")
+	body := []byte(`{"grupoTrabajo":"G0/TEST"}`)
+
+	tests := []struct {
+		name      string
+		signature string
+		want      int
+	}{
+		{name: "valid signature", signature: v.ComputeSignature(body), want: fiber.StatusOK},
+		{name: "missing signature", want: fiber.StatusUnauthorized},
+		{name: "wrong signature", signature: NewHMACValidator("other").ComputeSignature(body), want: fiber.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handled []byte
+			var crew string
+			app := fiber.New()
+			app.Post("/signed", RequireSignature(v), func(c *fiber.Ctx) error {
+				handled, _ = VerifiedBodyFromContext(c.UserContext())
+				crew, _ = CrewFromContext(c.UserContext())
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			req := httptest.NewRequest("POST", "/signed", bytes.NewReader(body))
+			if tt.signature != "" {
+				req.Header.Set(SignatureHeader, tt.signature)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test error: %v", err)
+			}
+			checkSignatureResponse(t, resp, tt.want)
+			if tt.want == fiber.StatusOK && !bytes.Equal(handled, body) {
+				t.Errorf("handler body = %q; want the verified body %q", handled, body)
+			}
+			if tt.want == fiber.StatusOK && crew != "G0/TEST" {
+				t.Errorf("crew = %q; want G0/TEST", crew)
+			}
+			if tt.want != fiber.StatusOK && handled != nil {
+				t.Error("handler must not run for rejected signatures")
+			}
+		})
+	}
+}
+
+func TestRequireSignatureBodyAvailableDownstream(t *testing.T) {
+	v := NewHMACValidator("test-secret")
+
+	// Every handler in the chain reads the same verified body, and bodies
+	// kept from earlier requests are not overwritten by later ones
+	var bodies [][]byte
+	app := fiber.New()
+	app.Post("/signed", RequireSignature(v), func(c *fiber.Ctx) error {
+		body, _ := VerifiedBodyFromContext(c.UserContext())
+		bodies = append(bodies, body)
+		return c.Next()
+	}, func(c *fiber.Ctx) error {
+		body, _ := VerifiedBodyFromContext(c.UserContext())
+		bodies = append(bodies, body)
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	sent := [][]byte{[]byte(`{"grupoTrabajo":"G0/A"}`), []byte(`{"grupoTrabajo":"G0/B"}`)}
+	for _, body := range sent {
+		req := httptest.NewRequest("POST", "/signed", bytes.NewReader(body))
+		req.Header.Set(SignatureHeader, v.ComputeSignature(body))
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("app.Test error: %v", err)
+		}
+		checkSignatureResponse(t, resp, fiber.StatusOK)
+	}
+
+	want := [][]byte{sent[0], sent[0], sent[1], sent[1]}
+	if len(bodies) != len(want) {
+		t.Fatalf("bodies read = %d; want %d", len(bodies), len(want))
+	}
+	for i := range want {
+		if !bytes.Equal(bodies[i], want[i]) {
+			t.Errorf("body %d = %q; want %q", i, bodies[i], want[i])
+		}
+	}
+}
+
+func checkSignatureResponse(t *testing.T, resp *http.Response, want int) {
+	t.Helper()
+	if resp.StatusCode != want {
+		t.Fatalf("status = %d; want %d", resp.StatusCode, want)
+	}
+	if want == http.StatusUnauthorized {
+		var apiErr apierror.Error
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil || apiErr.Code != apierror.CodeBadSignature {
+			t.Errorf("code = %q (err %v); want %s", apiErr.Code, err, apierror.CodeBadSignature)
+		}
+	}
+}
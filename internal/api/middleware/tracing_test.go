@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func installTestTracer(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	prevProvider := otel.GetTracerProvider()
+	prevPropagator := otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+	return recorder
+}
+
+func TestTracingStartsServerSpan(t *testing.T) {
+	recorder := installTestTracer(t)
+
+	var handlerSpan trace.SpanContext
+	app := fiber.New()
+	app.Use(Tracing())
+	app.Post("/crews/:id", func(c *fiber.Ctx) error {
+		handlerSpan = trace.SpanContextFromContext(c.UserContext())
+		return c.SendStatus(fiber.StatusAccepted)
+	})
+
+	req := httptest.NewRequest("POST", "/crews/123", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	if _, err := app.Test(req, -1); err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("ended spans = %d; want 1", len(spans))
+	}
+	span := spans[0]
+
+	if span.Name() != "POST /crews/:id" {
+		t.Errorf("span name = %q; want %q", span.Name(), "POST /crews/:id")
+	}
+	if span.SpanKind() != trace.SpanKindServer {
+		t.Errorf("span kind = %v; want server", span.SpanKind())
+	}
+	if got := span.SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace ID = %s; want the incoming traceparent trace ID", got)
+	}
+	if span.Parent().SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("parent span ID = %s; want incoming span ID", span.Parent().SpanID())
+	}
+	if handlerSpan.SpanID() != span.SpanContext().SpanID() {
+		t.Error("handler context should carry the server span")
+	}
+
+	var status int64
+	for _, attr := range span.Attributes() {
+		if attr.Key == attribute.Key("http.response.status_code") {
+			status = attr.Value.AsInt64()
+		}
+	}
+	if status != fiber.StatusAccepted {
+		t.Errorf("status attribute = %d; want %d", status, fiber.StatusAccepted)
+	}
+}
+
+func TestTracingWithoutIncomingContext(t *testing.T) {
+	recorder := installTestTracer(t)
+
+	app := fiber.New()
+	app.Use(Tracing())
+	app.Get("/health", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	if _, err := app.Test(httptest.NewRequest("GET", "/health", nil), -1); err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("ended spans = %d; want 1", len(spans))
+	}
+	if spans[0].Parent().IsValid() {
+		t.Error("span without incoming traceparent should be a root span")
+	}
+}
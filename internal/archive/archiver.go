@@ -0,0 +1,346 @@
+// Package archive guarda cada evento de tracking crudo en almacenamiento de
+// objetos compatible con S3 para su retención a largo plazo.
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nuid"
+)
+
+// uploadTimeout limita cada subida en segundo plano.
+const uploadTimeout = 30 * time.Second
+
+// ObjectStore sube objetos al almacenamiento de archivo.
+type ObjectStore interface {
+	PutObject(ctx context.Context, key string, body []byte) error
+}
+
+// Options configura el archivador.
+type Options struct {
+	// Prefix encabeza cada clave de objeto, por ejemplo "tracking".
+	Prefix string
+
+	// MaxBytes cierra el objeto en curso al superar este tamaño sin comprimir.
+	MaxBytes int
+
+	// MaxAge cierra el objeto en curso cuando su primer evento tiene esta antigüedad.
+	MaxAge time.Duration
+
+	// SpoolDir guarda los objetos que no pudieron subirse; se reintentan en
+	// cada cierre posterior.
+	SpoolDir string
+}
+
+// Archiver agrupa los eventos recibidos en objetos NDJSON comprimidos con
+// gzip, uno por hora UTC de recepción, con claves como
+// tracking/2024/01/15/13/part-<proceso>-0001.json.gz.
+//
+// Un objeto se cierra al alcanzar MaxBytes o MaxAge, al cambiar la hora y al
+// detener el archivador. Los objetos cerrados se suben en segundo plano para
+// no demorar la recepción de eventos. Si el almacenamiento no responde, el
+// objeto se guarda en SpoolDir y se reintenta después, de modo que un apagado
+// ordenado no pierde eventos. La entrega es al menos una vez.
+type Archiver struct {
+	store  ObjectStore
+	opts   Options
+	logger *slog.Logger
+	runID  string
+	now    func() time.Time
+
+	mu      sync.Mutex
+	window  time.Time
+	parts   map[time.Time]int
+	started time.Time
+	raw     int
+	buf     bytes.Buffer
+	gz      *gzip.Writer
+
+	// pending son los objetos cerrados que aún no se suben; wake avisa al
+	// subidor de segundo plano.
+	pending []*object
+	wake    chan struct{}
+
+	// uploadMu serializa las subidas para que el spool se procese en orden.
+	uploadMu sync.Mutex
+
+	sub       *nats.Subscription
+	subClosed chan struct{}
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// NewArchiver crea un archivador; los eventos se reciben con Subscribe o Add.
+func NewArchiver(store ObjectStore, opts Options, logger *slog.Logger) *Archiver {
+	return &Archiver{
+		store:  store,
+		opts:   opts,
+		logger: logger,
+		runID:  nuid.Next(),
+		now:    time.Now,
+		parts:  make(map[time.Time]int),
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Start inicia la subida en segundo plano de los objetos cerrados, el cierre
+// periódico por antigüedad y el reintento del spool.
+func (a *Archiver) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancel = cancel
+
+	interval := a.opts.MaxAge / 4
+	if interval < time.Second {
+		interval = time.Second
+	}
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-a.wake:
+				a.uploadPending(ctx)
+			case <-ticker.C:
+				a.flushIfExpired(ctx)
+			}
+		}
+	}()
+}
+
+// Subscribe recibe los eventos publicados en subject usando un grupo de cola,
+// de modo que varias instancias de la API archivan cada evento una sola vez.
+func (a *Archiver) Subscribe(conn *nats.Conn, subject string) error {
+	sub, err := conn.QueueSubscribe(subject, "gridflow-archiver", func(msg *nats.Msg) {
+		a.Add(msg.Data)
+	})
+	if err != nil {
+		return fmt.Errorf("fallo al suscribir el archivador a %s: %w", subject, err)
+	}
+	closed := make(chan struct{})
+	sub.SetClosedHandler(func(string) { close(closed) })
+
+	a.mu.Lock()
+	a.sub = sub
+	a.subClosed = closed
+	a.mu.Unlock()
+	a.logger.Info("Archivador suscrito", "subject", subject)
+	return nil
+}
+
+// Add agrega un evento crudo al objeto de la hora en curso. Los objetos que
+// se cierran quedan pendientes para el subidor de segundo plano o para Flush.
+func (a *Archiver) Add(event []byte) {
+	now := a.now().UTC()
+	window := now.Truncate(time.Hour)
+
+	a.mu.Lock()
+	if a.gz != nil && !window.Equal(a.window) {
+		a.closeLocked()
+	}
+	if a.gz == nil {
+		a.window = window
+		a.started = now
+		a.gz = gzip.NewWriter(&a.buf)
+	}
+	a.gz.Write(event)
+	a.gz.Write([]byte("\n"))
+	a.raw += len(event) + 1
+	if a.raw >= a.opts.MaxBytes {
+		a.closeLocked()
+	}
+	a.mu.Unlock()
+}
+
+// Stop deja de recibir eventos tras procesar los ya entregados por NATS, sube
+// los objetos pendientes y el objeto en curso y reintenta el spool. Lo que no
+// pueda subirse antes de que expire ctx queda en SpoolDir.
+func (a *Archiver) Stop(ctx context.Context) error {
+	a.mu.Lock()
+	sub, subClosed := a.sub, a.subClosed
+	a.mu.Unlock()
+	if sub != nil {
+		if err := sub.Drain(); err != nil {
+			if !errors.Is(err, nats.ErrConnectionClosed) {
+				a.logger.Warn("Fallo al drenar la suscripción del archivador", "error", err)
+			}
+		} else {
+			select {
+			case <-subClosed:
+			case <-ctx.Done():
+				a.logger.Warn("Suscripción del archivador sin drenar al expirar el apagado")
+			}
+		}
+	}
+
+	if a.cancel != nil {
+		a.cancel()
+	}
+	a.wg.Wait()
+
+	return a.Flush(ctx)
+}
+
+// Flush sube los objetos pendientes, el objeto en curso, si lo hay, y los
+// objetos del spool.
+func (a *Archiver) Flush(ctx context.Context) error {
+	a.mu.Lock()
+	a.closeLocked()
+	a.mu.Unlock()
+
+	if err := a.uploadPending(ctx); err != nil {
+		return err
+	}
+	return a.retrySpool(ctx)
+}
+
+func (a *Archiver) flushIfExpired(ctx context.Context) {
+	a.mu.Lock()
+	if a.gz != nil && a.now().Sub(a.started) >= a.opts.MaxAge {
+		a.closeLocked()
+	}
+	a.mu.Unlock()
+
+	a.uploadPending(ctx)
+	a.retrySpool(ctx)
+}
+
+// uploadPending sube en orden los objetos cerrados pendientes. Retorna el
+// primer error de un objeto que tampoco pudo guardarse en el spool.
+func (a *Archiver) uploadPending(ctx context.Context) error {
+	a.mu.Lock()
+	pending := a.pending
+	a.pending = nil
+	a.mu.Unlock()
+
+	var firstErr error
+	for _, obj := range pending {
+		uploadCtx, cancel := context.WithTimeout(ctx, uploadTimeout)
+		err := a.upload(uploadCtx, obj)
+		cancel()
+		if err != nil {
+			a.logger.Error("Objeto de archivo perdido", "clave", obj.key, "error", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// object es un objeto cerrado pendiente de subir.
+type object struct {
+	key  string
+	body []byte
+}
+
+// closeLocked cierra el objeto en curso, si lo hay, lo deja pendiente de
+// subir y avisa al subidor. Requiere a.mu.
+func (a *Archiver) closeLocked() {
+	if a.gz == nil {
+		return
+	}
+	a.gz.Close()
+
+	a.parts[a.window]++
+	obj := &object{
+		key:  a.key(a.window, a.parts[a.window]),
+		body: bytes.Clone(a.buf.Bytes()),
+	}
+
+	a.buf.Reset()
+	a.gz = nil
+	a.raw = 0
+	for window := range a.parts {
+		if window.Before(a.window) {
+			delete(a.parts, window)
+		}
+	}
+
+	a.pending = append(a.pending, obj)
+	select {
+	case a.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (a *Archiver) key(window time.Time, part int) string {
+	return path.Join(a.opts.Prefix, window.Format("2006/01/02/15"), fmt.Sprintf("part-%s-%04d.json.gz", a.runID, part))
+}
+
+// upload sube obj o, si falla, lo guarda en el spool. Solo retorna error si
+// tampoco pudo guardarse en disco.
+func (a *Archiver) upload(ctx context.Context, obj *object) error {
+	a.uploadMu.Lock()
+	defer a.uploadMu.Unlock()
+
+	err := a.store.PutObject(ctx, obj.key, obj.body)
+	if err == nil {
+		a.logger.Debug("Objeto archivado", "clave", obj.key, "bytes", len(obj.body))
+		return nil
+	}
+
+	if a.opts.SpoolDir == "" {
+		return fmt.Errorf("fallo al archivar %s sin directorio de spool: %w", obj.key, err)
+	}
+	a.logger.Warn("Almacenamiento de archivo no disponible; guardando en spool", "clave", obj.key, "error", err)
+	spoolPath := filepath.Join(a.opts.SpoolDir, filepath.FromSlash(obj.key))
+	if err := os.MkdirAll(filepath.Dir(spoolPath), 0o750); err != nil {
+		return fmt.Errorf("fallo al crear directorio de spool: %w", err)
+	}
+	if err := os.WriteFile(spoolPath, obj.body, 0o640); err != nil {
+		return fmt.Errorf("fallo al guardar %s en spool: %w", obj.key, err)
+	}
+	return nil
+}
+
+// retrySpool sube los objetos del spool y los elimina al confirmarse. Se
+// detiene en el primer fallo y retorna ese error.
+func (a *Archiver) retrySpool(ctx context.Context) error {
+	a.uploadMu.Lock()
+	defer a.uploadMu.Unlock()
+
+	if a.opts.SpoolDir == "" {
+		return nil
+	}
+	err := filepath.WalkDir(a.opts.SpoolDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(a.opts.SpoolDir, p)
+		if err != nil {
+			return err
+		}
+		body, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		key := filepath.ToSlash(rel)
+		if err := a.store.PutObject(ctx, key, body); err != nil {
+			return fmt.Errorf("fallo al subir %s desde el spool: %w", key, err)
+		}
+		a.logger.Info("Objeto del spool archivado", "clave", key)
+		return os.Remove(p)
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,304 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/120m4n/GridFlow-Dynamics/internal/messaging/natstest"
+)
+
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// storeFalso guarda los objetos en memoria y puede simular una caída.
+type storeFalso struct {
+	mu       sync.Mutex
+	objetos  map[string][]byte
+	caido    atomic.Bool
+	llamadas atomic.Int32
+}
+
+func nuevoStoreFalso() *storeFalso {
+	return &storeFalso{objetos: make(map[string][]byte)}
+}
+
+func (s *storeFalso) PutObject(_ context.Context, key string, body []byte) error {
+	s.llamadas.Add(1)
+	if s.caido.Load() {
+		return errors.New("almacenamiento no disponible")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objetos[key] = bytes.Clone(body)
+	return nil
+}
+
+func (s *storeFalso) claves() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var claves []string
+	for k := range s.objetos {
+		claves = append(claves, k)
+	}
+	sort.Strings(claves)
+	return claves
+}
+
+// lineas descomprime el objeto y retorna sus líneas NDJSON.
+func (s *storeFalso) lineas(t *testing.T, key string) []string {
+	t.Helper()
+	s.mu.Lock()
+	body := s.objetos[key]
+	s.mu.Unlock()
+
+	gz, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("Objeto %s no es gzip: %v", key, err)
+	}
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("Error al descomprimir %s: %v", key, err)
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+// relojFalso permite avanzar el tiempo del archivador.
+type relojFalso struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (r *relojFalso) Now() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.now
+}
+
+func (r *relojFalso) Set(t time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.now = t
+}
+
+func nuevoArchivador(t *testing.T, store ObjectStore, opts Options) (*Archiver, *relojFalso) {
+	t.Helper()
+	if opts.Prefix == "" {
+		opts.Prefix = "tracking"
+	}
+	if opts.MaxBytes == 0 {
+		opts.MaxBytes = 1 << 20
+	}
+	if opts.MaxAge == 0 {
+		opts.MaxAge = time.Minute
+	}
+	if opts.SpoolDir == "" {
+		opts.SpoolDir = t.TempDir()
+	}
+	reloj := &relojFalso{now: time.Date(2024, 1, 15, 13, 30, 0, 0, time.UTC)}
+	a := NewArchiver(store, opts, testLogger)
+	a.now = reloj.Now
+	return a, reloj
+}
+
+func TestArchiverCierraPorTamaño(t *testing.T) {
+	store := nuevoStoreFalso()
+	a, _ := nuevoArchivador(t, store, Options{MaxBytes: 30})
+
+	a.Add([]byte(`{"evento":1,"relleno":"xx"}`))
+	if len(store.claves()) != 0 {
+		t.Fatal("No se esperaba subida antes de alcanzar MaxBytes")
+	}
+	a.Add([]byte(`{"evento":2}`))
+	a.Add([]byte(`{"evento":3}`))
+	if err := a.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush error: %v", err)
+	}
+
+	claves := store.claves()
+	if len(claves) != 2 {
+		t.Fatalf("objetos = %v; esperado 2", claves)
+	}
+	for i, clave := range claves {
+		if !strings.HasPrefix(clave, "tracking/2024/01/15/13/part-") || !strings.HasSuffix(clave, ".json.gz") {
+			t.Errorf("clave = %q; formato inesperado", clave)
+		}
+		if !strings.HasSuffix(clave, []string{"-0001.json.gz", "-0002.json.gz"}[i]) {
+			t.Errorf("clave = %q; numeración de partes inesperada", clave)
+		}
+	}
+	if got := store.lineas(t, claves[0]); len(got) != 2 || got[1] != `{"evento":2}` {
+		t.Errorf("primer objeto = %v; esperado los eventos 1 y 2", got)
+	}
+	if got := store.lineas(t, claves[1]); len(got) != 1 || got[0] != `{"evento":3}` {
+		t.Errorf("segundo objeto = %v; esperado el evento 3", got)
+	}
+}
+
+func TestArchiverCierraAlCambiarLaHora(t *testing.T) {
+	store := nuevoStoreFalso()
+	a, reloj := nuevoArchivador(t, store, Options{})
+
+	reloj.Set(time.Date(2024, 1, 15, 13, 59, 59, 0, time.UTC))
+	a.Add([]byte(`{"evento":1}`))
+	reloj.Set(time.Date(2024, 1, 15, 14, 0, 0, 0, time.UTC))
+	a.Add([]byte(`{"evento":2}`))
+
+	// Add no sube: el objeto cerrado queda para el subidor de segundo plano
+	if len(store.claves()) != 0 {
+		t.Fatal("Add no debe subir objetos en la goroutine del llamador")
+	}
+	a.uploadPending(context.Background())
+	claves := store.claves()
+	if len(claves) != 1 || !strings.HasPrefix(claves[0], "tracking/2024/01/15/13/") {
+		t.Fatalf("objetos = %v; esperado uno en la hora 13", claves)
+	}
+
+	a.Flush(context.Background())
+	claves = store.claves()
+	if len(claves) != 2 || !strings.HasPrefix(claves[1], "tracking/2024/01/15/14/") || !strings.HasSuffix(claves[1], "-0001.json.gz") {
+		t.Errorf("objetos = %v; esperado la parte 1 de la hora 14", claves)
+	}
+}
+
+func TestArchiverCierraPorAntiguedad(t *testing.T) {
+	store := nuevoStoreFalso()
+	a, reloj := nuevoArchivador(t, store, Options{MaxAge: 5 * time.Minute})
+
+	a.Add([]byte(`{"evento":1}`))
+	reloj.Set(reloj.Now().Add(4 * time.Minute))
+	a.flushIfExpired(context.Background())
+	if len(store.claves()) != 0 {
+		t.Fatal("No se esperaba subida antes de MaxAge")
+	}
+
+	reloj.Set(reloj.Now().Add(time.Minute))
+	a.flushIfExpired(context.Background())
+	if len(store.claves()) != 1 {
+		t.Errorf("objetos = %v; esperado 1 tras MaxAge", store.claves())
+	}
+}
+
+func TestArchiverSpoolSinConexion(t *testing.T) {
+	store := nuevoStoreFalso()
+	spool := t.TempDir()
+	a, _ := nuevoArchivador(t, store, Options{SpoolDir: spool})
+
+	store.caido.Store(true)
+	a.Add([]byte(`{"evento":1}`))
+	if err := a.Stop(context.Background()); err == nil {
+		t.Error("Stop error = nil; esperado error con el almacenamiento caído")
+	}
+
+	var spooled []string
+	filepath.WalkDir(spool, func(p string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			spooled = append(spooled, p)
+		}
+		return err
+	})
+	if len(spooled) != 1 {
+		t.Fatalf("archivos en spool = %v; esperado 1", spooled)
+	}
+
+	// Otro proceso con el mismo spool sube lo pendiente al recuperarse
+	store.caido.Store(false)
+	b, _ := nuevoArchivador(t, store, Options{SpoolDir: spool})
+	if err := b.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush error: %v", err)
+	}
+
+	claves := store.claves()
+	if len(claves) != 1 || !strings.HasPrefix(claves[0], "tracking/2024/01/15/13/") {
+		t.Fatalf("objetos = %v; esperado el objeto del spool con su clave original", claves)
+	}
+	if got := store.lineas(t, claves[0]); len(got) != 1 || got[0] != `{"evento":1}` {
+		t.Errorf("objeto = %v; esperado el evento 1", got)
+	}
+	if _, err := os.Stat(spooled[0]); !os.IsNotExist(err) {
+		t.Error("El archivo del spool debe eliminarse tras subirse")
+	}
+}
+
+func TestArchiverSuscripcionNATS(t *testing.T) {
+	nc, err := nats.Connect(natstest.Server(t).ClientURL())
+	if err != nil {
+		t.Fatalf("Error al conectar a NATS: %v", err)
+	}
+	t.Cleanup(nc.Close)
+
+	store := nuevoStoreFalso()
+	a, _ := nuevoArchivador(t, store, Options{})
+	a.Start()
+	if err := a.Subscribe(nc, "inventario.cuadrilla"); err != nil {
+		t.Fatalf("Subscribe error: %v", err)
+	}
+
+	nc.Publish("inventario.cuadrilla", []byte(`{"grupo_trabajo":"G0/A"}`))
+	nc.Publish("inventario.cuadrilla", []byte(`{"grupo_trabajo":"G0/B"}`))
+	if err := nc.Flush(); err != nil {
+		t.Fatalf("Flush NATS error: %v", err)
+	}
+
+	// El apagado ordenado procesa lo recibido y sube el objeto en curso
+	if err := a.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop error: %v", err)
+	}
+	claves := store.claves()
+	if len(claves) != 1 {
+		t.Fatalf("objetos = %v; esperado 1", claves)
+	}
+	if got := store.lineas(t, claves[0]); len(got) != 2 {
+		t.Errorf("eventos archivados = %v; esperado 2", got)
+	}
+}
+
+func TestArchiverStopNoPierdeEventos(t *testing.T) {
+	nc, err := nats.Connect(natstest.Server(t).ClientURL())
+	if err != nil {
+		t.Fatalf("Error al conectar a NATS: %v", err)
+	}
+	t.Cleanup(nc.Close)
+
+	// Objetos pequeños para que se cierren y suban durante la ráfaga
+	store := nuevoStoreFalso()
+	a, _ := nuevoArchivador(t, store, Options{MaxBytes: 512})
+	a.Start()
+	if err := a.Subscribe(nc, "inventario.cuadrilla"); err != nil {
+		t.Fatalf("Subscribe error: %v", err)
+	}
+
+	const total = 2000
+	for i := 0; i < total; i++ {
+		nc.Publish("inventario.cuadrilla", []byte(fmt.Sprintf(`{"evento":%d}`, i)))
+	}
+	if err := nc.Flush(); err != nil {
+		t.Fatalf("Flush NATS error: %v", err)
+	}
+	if err := a.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop error: %v", err)
+	}
+
+	vistos := make(map[string]bool)
+	for _, clave := range store.claves() {
+		for _, linea := range store.lineas(t, clave) {
+			vistos[linea] = true
+		}
+	}
+	if len(vistos) != total {
+		t.Errorf("eventos archivados = %d; esperado %d", len(vistos), total)
+	}
+}
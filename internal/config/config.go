@@ -2,46 +2,699 @@
 package config
 
 import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/120m4n/GridFlow-Dynamics/internal/logging"
+)
+
+const (
+	// EnvProduction is the APP_ENV value that enables production-only checks.
+	EnvProduction = "production"
+
+	// DefaultHMACSecret is the development secret used when HMAC_SECRET is unset.
+	DefaultHMACSecret = "default-secret-change-in-production"
 )
 
 // Config holds all configuration for the application.
 type Config struct {
-	NATS   NATSConfig
-	Server ServerConfig
-	API    APIConfig
+	Env     string        `yaml:"env"`
+	NATS    NATSConfig    `yaml:"nats"`
+	Server  ServerConfig  `yaml:"server"`
+	API     APIConfig     `yaml:"api"`
+	Admin   AdminConfig   `yaml:"admin"`
+	MQTT    MQTTConfig    `yaml:"mqtt"`
+	GRPC    GRPCConfig    `yaml:"grpc"`
+	Archive ArchiveConfig `yaml:"archive"`
+	Log     LogConfig     `yaml:"log"`
+}
+
+// AdminConfig holds settings for operator-only endpoints.
+type AdminConfig struct {
+	// Token is the bearer token required by admin endpoints.
+	Token string `yaml:"token"`
+
+	// DebugEndpoints mounts pprof and runtime debug handlers under /debug.
+	DebugEndpoints bool `yaml:"debug_endpoints"`
+}
+
+// LogConfig holds logging settings.
+type LogConfig struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
 }
 
 // NATSConfig holds NATS connection settings.
 type NATSConfig struct {
-	URL string
+	URL string `yaml:"url"`
+
+	// SubjectPrefix is prepended to every subject, e.g. "gridflow.prod".
+	SubjectPrefix     string `yaml:"subject_prefix"`
+	SubjectInventario string `yaml:"subject_inventario"`
+
+	// CloudEvents wraps every published payload in a CloudEvents 1.0
+	// structured-mode envelope.
+	CloudEvents bool `yaml:"cloudevents"`
+
+	// PublishRetries is how many times a publish is retried after a transient
+	// error, waiting PublishRetryBackoff (doubled each attempt, with jitter).
+	PublishRetries      int           `yaml:"publish_retries"`
+	PublishRetryBackoff time.Duration `yaml:"publish_retry_backoff"`
+
+	// BreakerThreshold consecutive publish failures on a subject open its
+	// circuit for BreakerCooldown; 0 disables the circuit breaker.
+	BreakerThreshold int           `yaml:"breaker_threshold"`
+	BreakerCooldown  time.Duration `yaml:"breaker_cooldown"`
+}
+
+// Subject returns name qualified with the configured prefix.
+func (n NATSConfig) Subject(name string) string {
+	if n.SubjectPrefix == "" {
+		return name
+	}
+	return n.SubjectPrefix + "." + name
+}
+
+// InventarioSubject returns the subject crew inventory events are published to.
+func (n NATSConfig) InventarioSubject() string {
+	return n.Subject(n.SubjectInventario)
+}
+
+// MQTTConfig holds settings for the optional MQTT ingestion bridge. The bridge
+// is disabled when URL is empty.
+type MQTTConfig struct {
+	URL      string `yaml:"url"`
+	Topic    string `yaml:"topic"`
+	ClientID string `yaml:"client_id"`
+
+	// Username and Password authenticate the bridge against the broker.
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+
+	// TLSCAFile verifies the broker; TLSCertFile and TLSKeyFile present a
+	// client certificate.
+	TLSCAFile   string `yaml:"tls_ca_file"`
+	TLSCertFile string `yaml:"tls_cert_file"`
+	TLSKeyFile  string `yaml:"tls_key_file"`
+}
+
+// Enabled reports whether a broker URL is configured.
+func (m MQTTConfig) Enabled() bool {
+	return m.URL != ""
+}
+
+// TLSConfig loads the configured CA and client certificate. It returns nil
+// when no TLS files are configured.
+func (m MQTTConfig) TLSConfig() (*tls.Config, error) {
+	if m.TLSCAFile == "" && m.TLSCertFile == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if m.TLSCAFile != "" {
+		caPEM, err := os.ReadFile(m.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("fallo al leer CA del broker MQTT: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("CA del broker MQTT %s no contiene certificados PEM válidos", m.TLSCAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if m.TLSCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(m.TLSCertFile, m.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("fallo al cargar certificado de cliente MQTT: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// GRPCConfig holds settings for the optional gRPC ingestion server. The
+// server is disabled when Port is empty.
+type GRPCConfig struct {
+	Port string `yaml:"port"`
+
+	// CrewTokens maps each crew ID to the token it must present in the
+	// call metadata.
+	CrewTokens map[string]string `yaml:"crew_tokens"`
+}
+
+// Enabled reports whether a gRPC port is configured.
+func (g GRPCConfig) Enabled() bool {
+	return g.Port != ""
+}
+
+// ArchiveConfig holds settings for archiving raw tracking events to S3 or a
+// compatible object store. The archiver is disabled when Endpoint is empty.
+type ArchiveConfig struct {
+	Endpoint  string `yaml:"endpoint"`
+	Bucket    string `yaml:"bucket"`
+	Region    string `yaml:"region"`
+	AccessKey string `yaml:"access_key"`
+	SecretKey string `yaml:"secret_key"`
+	UseSSL    bool   `yaml:"use_ssl"`
+
+	// Prefix is the first segment of every object key.
+	Prefix string `yaml:"prefix"`
+
+	// SpoolDir keeps objects that could not be uploaded until the store is back.
+	SpoolDir string `yaml:"spool_dir"`
+
+	// MaxBytes and MaxAge close the current object by uncompressed size or
+	// by the age of its first event.
+	MaxBytes int           `yaml:"max_bytes"`
+	MaxAge   time.Duration `yaml:"max_age"`
+}
+
+// Enabled reports whether an object store endpoint is configured.
+func (a ArchiveConfig) Enabled() bool {
+	return a.Endpoint != ""
 }
 
 // ServerConfig holds server settings.
 type ServerConfig struct {
-	Port string
+	ListenAddress string `yaml:"listen_address"`
+	Port          string `yaml:"port"`
+
+	// TLS is enabled when both TLSCertFile and TLSKeyFile are set.
+	// TLSClientCAFile additionally requires client certificates (mTLS).
+	TLSCertFile     string `yaml:"tls_cert_file"`
+	TLSKeyFile      string `yaml:"tls_key_file"`
+	TLSClientCAFile string `yaml:"tls_client_ca_file"`
+
+	// HTTPRedirectPort, if set with TLS enabled, serves plain HTTP redirects to HTTPS.
+	HTTPRedirectPort string `yaml:"http_redirect_port"`
+
+	// ReadTimeout bounds reading a whole request, body included; raise it for
+	// large uploads over slow links. WriteTimeout bounds writing the response
+	// and may be 0 to disable it for long-lived responses. IdleTimeout bounds
+	// how long a keep-alive connection waits for the next request.
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+}
+
+// Addr returns the host:port the HTTP server listens on.
+func (s ServerConfig) Addr() string {
+	return net.JoinHostPort(s.ListenAddress, s.Port)
+}
+
+// TLSEnabled reports whether certificate files are configured.
+func (s ServerConfig) TLSEnabled() bool {
+	return s.TLSCertFile != "" && s.TLSKeyFile != ""
+}
+
+// TLSConfig loads the configured certificates. It returns nil when TLS is not
+// enabled, and an error if any configured file cannot be read or parsed.
+func (s ServerConfig) TLSConfig() (*tls.Config, error) {
+	if !s.TLSEnabled() {
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(s.TLSCertFile, s.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("fallo al cargar certificado TLS: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if s.TLSClientCAFile != "" {
+		caPEM, err := os.ReadFile(s.TLSClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("fallo al leer CA de clientes: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("CA de clientes %s no contiene certificados PEM válidos", s.TLSClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
 }
 
 // APIConfig holds API settings.
 type APIConfig struct {
-	HMACSecret      string
-	RateLimitPerMin int
+	HMACSecret      string `yaml:"hmac_secret"`
+	RateLimitPerMin int    `yaml:"rate_limit_per_min"`
 }
 
-// Load reads configuration from environment variables with defaults.
-func Load() *Config {
-	return &Config{
+// Load builds the configuration from defaults, then the optional YAML file
+// named by CONFIG_FILE, then environment variables, each layer overriding the
+// previous one.
+func Load() (*Config, error) {
+	cfg := &Config{
+		Env: "development",
 		NATS: NATSConfig{
-			URL: getEnv("NATS_URL", "nats://localhost:4222"),
+			URL:                 "nats://localhost:4222",
+			SubjectInventario:   "inventario.cuadrilla",
+			PublishRetries:      2,
+			PublishRetryBackoff: 100 * time.Millisecond,
+			BreakerThreshold:    5,
+			BreakerCooldown:     10 * time.Second,
 		},
 		Server: ServerConfig{
-			Port: getEnv("SERVER_PORT", "9080"),
+			Port:         "9080",
+			ReadTimeout:  15 * time.Second,
+			WriteTimeout: 15 * time.Second,
+			IdleTimeout:  60 * time.Second,
 		},
 		API: APIConfig{
-			HMACSecret:      getEnv("HMAC_SECRET", "default-secret-change-in-production"),
+			HMACSecret:      DefaultHMACSecret,
 			RateLimitPerMin: 100,
 		},
+		MQTT: MQTTConfig{
+			Topic:    "gridflow/tracking/+",
+			ClientID: "gridflow-mqtt-bridge",
+		},
+		Archive: ArchiveConfig{
+			Prefix:   "tracking",
+			SpoolDir: "/var/lib/gridflow/archive-spool",
+			MaxBytes: 64 << 20,
+			MaxAge:   5 * time.Minute,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: logging.FormatText,
+		},
+	}
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := loadFile(path, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg.Env = getEnv("APP_ENV", cfg.Env)
+	cfg.NATS.URL = getEnv("NATS_URL", cfg.NATS.URL)
+	cfg.NATS.SubjectPrefix = getEnv("NATS_SUBJECT_PREFIX", cfg.NATS.SubjectPrefix)
+	cfg.NATS.SubjectInventario = getEnv("NATS_SUBJECT_INVENTARIO", cfg.NATS.SubjectInventario)
+	cloudEvents, err := getEnvBool("NATS_CLOUDEVENTS", cfg.NATS.CloudEvents)
+	if err != nil {
+		return nil, err
+	}
+	cfg.NATS.CloudEvents = cloudEvents
+	publishRetries, err := getEnvInt("NATS_PUBLISH_RETRIES", cfg.NATS.PublishRetries)
+	if err != nil {
+		return nil, err
+	}
+	cfg.NATS.PublishRetries = publishRetries
+	publishRetryBackoff, err := getEnvDuration("NATS_PUBLISH_RETRY_BACKOFF", cfg.NATS.PublishRetryBackoff)
+	if err != nil {
+		return nil, err
+	}
+	cfg.NATS.PublishRetryBackoff = publishRetryBackoff
+	breakerThreshold, err := getEnvInt("NATS_BREAKER_THRESHOLD", cfg.NATS.BreakerThreshold)
+	if err != nil {
+		return nil, err
+	}
+	cfg.NATS.BreakerThreshold = breakerThreshold
+	breakerCooldown, err := getEnvDuration("NATS_BREAKER_COOLDOWN", cfg.NATS.BreakerCooldown)
+	if err != nil {
+		return nil, err
+	}
+	cfg.NATS.BreakerCooldown = breakerCooldown
+	cfg.Server.ListenAddress = getEnv("SERVER_LISTEN_ADDRESS", cfg.Server.ListenAddress)
+	cfg.Server.Port = getEnv("SERVER_PORT", cfg.Server.Port)
+	cfg.Server.TLSCertFile = getEnv("TLS_CERT_FILE", cfg.Server.TLSCertFile)
+	cfg.Server.TLSKeyFile = getEnv("TLS_KEY_FILE", cfg.Server.TLSKeyFile)
+	cfg.Server.TLSClientCAFile = getEnv("TLS_CLIENT_CA_FILE", cfg.Server.TLSClientCAFile)
+	cfg.Server.HTTPRedirectPort = getEnv("HTTP_REDIRECT_PORT", cfg.Server.HTTPRedirectPort)
+	readTimeout, err := getEnvDuration("SERVER_READ_TIMEOUT", cfg.Server.ReadTimeout)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Server.ReadTimeout = readTimeout
+	writeTimeout, err := getEnvDuration("SERVER_WRITE_TIMEOUT", cfg.Server.WriteTimeout)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Server.WriteTimeout = writeTimeout
+	idleTimeout, err := getEnvDuration("SERVER_IDLE_TIMEOUT", cfg.Server.IdleTimeout)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Server.IdleTimeout = idleTimeout
+	cfg.API.HMACSecret = getEnv("HMAC_SECRET", cfg.API.HMACSecret)
+	rateLimit, err := getEnvInt("RATE_LIMIT_PER_MIN", cfg.API.RateLimitPerMin)
+	if err != nil {
+		return nil, err
 	}
+	cfg.API.RateLimitPerMin = rateLimit
+	cfg.Admin.Token = getEnv("ADMIN_TOKEN", cfg.Admin.Token)
+	debugEndpoints, err := getEnvBool("DEBUG_ENDPOINTS", cfg.Admin.DebugEndpoints)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Admin.DebugEndpoints = debugEndpoints
+	cfg.MQTT.URL = getEnv("MQTT_URL", cfg.MQTT.URL)
+	cfg.MQTT.Topic = getEnv("MQTT_TOPIC", cfg.MQTT.Topic)
+	cfg.MQTT.ClientID = getEnv("MQTT_CLIENT_ID", cfg.MQTT.ClientID)
+	cfg.MQTT.Username = getEnv("MQTT_USERNAME", cfg.MQTT.Username)
+	cfg.MQTT.Password = getEnv("MQTT_PASSWORD", cfg.MQTT.Password)
+	cfg.MQTT.TLSCAFile = getEnv("MQTT_TLS_CA_FILE", cfg.MQTT.TLSCAFile)
+	cfg.MQTT.TLSCertFile = getEnv("MQTT_TLS_CERT_FILE", cfg.MQTT.TLSCertFile)
+	cfg.MQTT.TLSKeyFile = getEnv("MQTT_TLS_KEY_FILE", cfg.MQTT.TLSKeyFile)
+	cfg.GRPC.Port = getEnv("GRPC_PORT", cfg.GRPC.Port)
+	if raw := os.Getenv("GRPC_CREW_TOKENS"); raw != "" {
+		tokens, err := parseCrewTokens(raw)
+		if err != nil {
+			return nil, err
+		}
+		cfg.GRPC.CrewTokens = tokens
+	}
+	cfg.Archive.Endpoint = getEnv("ARCHIVE_S3_ENDPOINT", cfg.Archive.Endpoint)
+	cfg.Archive.Bucket = getEnv("ARCHIVE_S3_BUCKET", cfg.Archive.Bucket)
+	cfg.Archive.Region = getEnv("ARCHIVE_S3_REGION", cfg.Archive.Region)
+	cfg.Archive.AccessKey = getEnv("ARCHIVE_S3_ACCESS_KEY", cfg.Archive.AccessKey)
+	cfg.Archive.SecretKey = getEnv("ARCHIVE_S3_SECRET_KEY", cfg.Archive.SecretKey)
+	useSSL, err := getEnvBool("ARCHIVE_S3_USE_SSL", cfg.Archive.UseSSL)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Archive.UseSSL = useSSL
+	cfg.Archive.Prefix = getEnv("ARCHIVE_PREFIX", cfg.Archive.Prefix)
+	cfg.Archive.SpoolDir = getEnv("ARCHIVE_SPOOL_DIR", cfg.Archive.SpoolDir)
+	maxBytes, err := getEnvInt("ARCHIVE_MAX_BYTES", cfg.Archive.MaxBytes)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Archive.MaxBytes = maxBytes
+	maxAge, err := getEnvDuration("ARCHIVE_MAX_AGE", cfg.Archive.MaxAge)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Archive.MaxAge = maxAge
+	cfg.Log.Level = getEnv("LOG_LEVEL", cfg.Log.Level)
+	cfg.Log.Format = getEnv("LOG_FORMAT", cfg.Log.Format)
+
+	return cfg, nil
+}
+
+// loadFile overlays the YAML file at path onto cfg. Keys that do not map to a
+// config field are logged as warnings instead of being silently ignored.
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("fallo al leer archivo de configuración: %w", err)
+	}
+
+	strict := *cfg
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	err = dec.Decode(&strict)
+	if err == nil || errors.Is(err, io.EOF) {
+		*cfg = strict
+		return nil
+	}
+
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		return fmt.Errorf("archivo de configuración %s inválido: %w", path, err)
+	}
+
+	// Unknown keys and type mismatches both surface as a TypeError in strict
+	// mode; a lenient decode tells them apart.
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("archivo de configuración %s inválido: %w", path, err)
+	}
+	for _, msg := range typeErr.Errors {
+		slog.Warn("Clave desconocida en archivo de configuración", "archivo", path, "detalle", msg)
+	}
+	return nil
+}
+
+// Validate checks the configuration and returns every problem found joined
+// into a single error, or nil if the configuration is usable.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if err := validateNATSURL(c.NATS.URL); err != nil {
+		errs = append(errs, err)
+	}
+
+	if c.NATS.SubjectPrefix != "" && !validSubject(c.NATS.SubjectPrefix) {
+		errs = append(errs, fmt.Errorf("NATS_SUBJECT_PREFIX inválido, recibido: %q", c.NATS.SubjectPrefix))
+	}
+	if !validSubject(c.NATS.SubjectInventario) {
+		errs = append(errs, fmt.Errorf("NATS_SUBJECT_INVENTARIO inválido, recibido: %q", c.NATS.SubjectInventario))
+	}
+	if c.NATS.PublishRetries < 0 {
+		errs = append(errs, fmt.Errorf("NATS_PUBLISH_RETRIES no puede ser negativo, recibido: %d", c.NATS.PublishRetries))
+	} else if c.NATS.PublishRetries > 0 && c.NATS.PublishRetryBackoff <= 0 {
+		errs = append(errs, fmt.Errorf("NATS_PUBLISH_RETRY_BACKOFF debe ser mayor que 0 con reintentos, recibido: %s", c.NATS.PublishRetryBackoff))
+	}
+	if c.NATS.BreakerThreshold < 0 {
+		errs = append(errs, fmt.Errorf("NATS_BREAKER_THRESHOLD no puede ser negativo, recibido: %d", c.NATS.BreakerThreshold))
+	} else if c.NATS.BreakerThreshold > 0 && c.NATS.BreakerCooldown <= 0 {
+		errs = append(errs, fmt.Errorf("NATS_BREAKER_COOLDOWN debe ser mayor que 0 con el circuit breaker habilitado, recibido: %s", c.NATS.BreakerCooldown))
+	}
+
+	if !validPort(c.Server.Port) {
+		errs = append(errs, fmt.Errorf("SERVER_PORT debe ser un puerto entre 1 y 65535, recibido: %q", c.Server.Port))
+	}
+
+	if (c.Server.TLSCertFile == "") != (c.Server.TLSKeyFile == "") {
+		errs = append(errs, fmt.Errorf("TLS_CERT_FILE y TLS_KEY_FILE deben configurarse juntos"))
+	}
+	if c.Server.TLSClientCAFile != "" && !c.Server.TLSEnabled() {
+		errs = append(errs, fmt.Errorf("TLS_CLIENT_CA_FILE requiere TLS_CERT_FILE y TLS_KEY_FILE"))
+	}
+	if c.Server.HTTPRedirectPort != "" {
+		if !validPort(c.Server.HTTPRedirectPort) {
+			errs = append(errs, fmt.Errorf("HTTP_REDIRECT_PORT debe ser un puerto entre 1 y 65535, recibido: %q", c.Server.HTTPRedirectPort))
+		} else if !c.Server.TLSEnabled() {
+			errs = append(errs, fmt.Errorf("HTTP_REDIRECT_PORT requiere TLS habilitado"))
+		} else if c.Server.HTTPRedirectPort == c.Server.Port {
+			errs = append(errs, fmt.Errorf("HTTP_REDIRECT_PORT debe ser distinto de SERVER_PORT"))
+		}
+	}
+	if c.Server.ReadTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("SERVER_READ_TIMEOUT debe ser mayor que 0, recibido: %s", c.Server.ReadTimeout))
+	}
+	if c.Server.WriteTimeout < 0 {
+		errs = append(errs, fmt.Errorf("SERVER_WRITE_TIMEOUT no puede ser negativo (0 lo deshabilita), recibido: %s", c.Server.WriteTimeout))
+	}
+	if c.Server.IdleTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("SERVER_IDLE_TIMEOUT debe ser mayor que 0, recibido: %s", c.Server.IdleTimeout))
+	}
+
+	if c.API.HMACSecret == "" {
+		errs = append(errs, fmt.Errorf("HMAC_SECRET es requerido y no puede estar vacío"))
+	} else if c.Env == EnvProduction && c.API.HMACSecret == DefaultHMACSecret {
+		errs = append(errs, fmt.Errorf("HMAC_SECRET no puede usar el valor por defecto en producción"))
+	}
+
+	if c.API.RateLimitPerMin <= 0 {
+		errs = append(errs, fmt.Errorf("el rate limit debe ser mayor que 0, recibido: %d", c.API.RateLimitPerMin))
+	}
+
+	if c.Admin.DebugEndpoints && c.Admin.Token == "" {
+		errs = append(errs, fmt.Errorf("DEBUG_ENDPOINTS requiere ADMIN_TOKEN"))
+	}
+
+	if c.MQTT.Enabled() {
+		errs = append(errs, validateMQTT(c.MQTT)...)
+	}
+
+	if c.GRPC.Enabled() {
+		if !validPort(c.GRPC.Port) {
+			errs = append(errs, fmt.Errorf("GRPC_PORT debe ser un puerto entre 1 y 65535, recibido: %q", c.GRPC.Port))
+		} else if c.GRPC.Port == c.Server.Port {
+			errs = append(errs, fmt.Errorf("GRPC_PORT debe ser distinto de SERVER_PORT"))
+		}
+		if len(c.GRPC.CrewTokens) == 0 {
+			errs = append(errs, fmt.Errorf("GRPC_CREW_TOKENS es requerido cuando GRPC_PORT está configurado"))
+		}
+	}
+
+	if c.Archive.Enabled() {
+		if c.Archive.Bucket == "" {
+			errs = append(errs, fmt.Errorf("ARCHIVE_S3_BUCKET es requerido cuando ARCHIVE_S3_ENDPOINT está configurado"))
+		}
+		if c.Archive.SpoolDir == "" {
+			errs = append(errs, fmt.Errorf("ARCHIVE_SPOOL_DIR es requerido cuando ARCHIVE_S3_ENDPOINT está configurado"))
+		}
+		if c.Archive.MaxBytes <= 0 {
+			errs = append(errs, fmt.Errorf("ARCHIVE_MAX_BYTES debe ser mayor que 0, recibido: %d", c.Archive.MaxBytes))
+		}
+		if c.Archive.MaxAge <= 0 {
+			errs = append(errs, fmt.Errorf("ARCHIVE_MAX_AGE debe ser mayor que 0, recibido: %s", c.Archive.MaxAge))
+		}
+	}
+
+	if _, err := logging.ParseLevel(c.Log.Level); err != nil {
+		errs = append(errs, fmt.Errorf("LOG_LEVEL debe ser debug, info, warn o error, recibido: %q", c.Log.Level))
+	}
+	switch c.Log.Format {
+	case logging.FormatText, logging.FormatJSON:
+	default:
+		errs = append(errs, fmt.Errorf("LOG_FORMAT debe ser text o json, recibido: %q", c.Log.Format))
+	}
+
+	return errors.Join(errs...)
+}
+
+// RestartRequired lists the settings that differ between c and next but are
+// only read at startup, so a reload cannot apply them.
+func (c *Config) RestartRequired(next *Config) []string {
+	var fields []string
+	if c.Env != next.Env {
+		fields = append(fields, "APP_ENV")
+	}
+	if c.NATS != next.NATS {
+		fields = append(fields, "NATS_*")
+	}
+	if c.Server.ListenAddress != next.Server.ListenAddress {
+		fields = append(fields, "SERVER_LISTEN_ADDRESS")
+	}
+	if c.Server.Port != next.Server.Port {
+		fields = append(fields, "SERVER_PORT")
+	}
+	if c.Server.TLSCertFile != next.Server.TLSCertFile ||
+		c.Server.TLSKeyFile != next.Server.TLSKeyFile ||
+		c.Server.TLSClientCAFile != next.Server.TLSClientCAFile {
+		fields = append(fields, "TLS_*")
+	}
+	if c.Server.HTTPRedirectPort != next.Server.HTTPRedirectPort {
+		fields = append(fields, "HTTP_REDIRECT_PORT")
+	}
+	if c.Server.ReadTimeout != next.Server.ReadTimeout ||
+		c.Server.WriteTimeout != next.Server.WriteTimeout ||
+		c.Server.IdleTimeout != next.Server.IdleTimeout {
+		fields = append(fields, "SERVER_*_TIMEOUT")
+	}
+	if c.MQTT != next.MQTT {
+		fields = append(fields, "MQTT_*")
+	}
+	if c.GRPC.Port != next.GRPC.Port || !maps.Equal(c.GRPC.CrewTokens, next.GRPC.CrewTokens) {
+		fields = append(fields, "GRPC_*")
+	}
+	if c.Archive != next.Archive {
+		fields = append(fields, "ARCHIVE_*")
+	}
+	if c.Log.Format != next.Log.Format {
+		fields = append(fields, "LOG_FORMAT")
+	}
+	if c.API.HMACSecret != next.API.HMACSecret {
+		fields = append(fields, "HMAC_SECRET")
+	}
+	if c.Admin.Token != next.Admin.Token {
+		fields = append(fields, "ADMIN_TOKEN")
+	}
+	if c.Admin.DebugEndpoints != next.Admin.DebugEndpoints {
+		fields = append(fields, "DEBUG_ENDPOINTS")
+	}
+	return fields
+}
+
+// validSubject reports whether s is a literal NATS subject: dot-separated,
+// non-empty tokens without wildcards or whitespace.
+func validSubject(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, token := range strings.Split(s, ".") {
+		if token == "" || strings.ContainsAny(token, "*> \t\r\n") {
+			return false
+		}
+	}
+	return true
+}
+
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n >= 1 && n <= 65535
+}
+
+// validateNATSURL accepts the comma-separated server list understood by nats.Connect.
+func validateNATSURL(raw string) error {
+	if strings.TrimSpace(raw) == "" {
+		return fmt.Errorf("NATS_URL es requerida y no puede estar vacía")
+	}
+	for _, server := range strings.Split(raw, ",") {
+		server = strings.TrimSpace(server)
+		u, err := url.Parse(server)
+		if err != nil {
+			return fmt.Errorf("NATS_URL inválida: %w", err)
+		}
+		switch u.Scheme {
+		case "nats", "tls", "ws", "wss":
+		default:
+			return fmt.Errorf("NATS_URL debe usar el esquema nats, tls, ws o wss, recibido: %q", server)
+		}
+		if u.Host == "" {
+			return fmt.Errorf("NATS_URL debe incluir host, recibido: %q", server)
+		}
+	}
+	return nil
+}
+
+// validateMQTT checks the bridge settings; it is only called when MQTT_URL is set.
+func validateMQTT(m MQTTConfig) []error {
+	var errs []error
+
+	u, err := url.Parse(m.URL)
+	switch {
+	case err != nil:
+		errs = append(errs, fmt.Errorf("MQTT_URL inválida: %w", err))
+	case u.Host == "":
+		errs = append(errs, fmt.Errorf("MQTT_URL debe incluir host, recibido: %q", m.URL))
+	default:
+		switch u.Scheme {
+		case "tcp", "mqtt", "ssl", "tls", "mqtts", "ws", "wss":
+		default:
+			errs = append(errs, fmt.Errorf("MQTT_URL debe usar el esquema tcp, mqtt, ssl, tls, mqtts, ws o wss, recibido: %q", m.URL))
+		}
+	}
+
+	if strings.TrimSpace(m.Topic) == "" {
+		errs = append(errs, fmt.Errorf("MQTT_TOPIC es requerido cuando MQTT_URL está configurada"))
+	}
+	if strings.TrimSpace(m.ClientID) == "" {
+		errs = append(errs, fmt.Errorf("MQTT_CLIENT_ID es requerido cuando MQTT_URL está configurada"))
+	}
+	if (m.TLSCertFile == "") != (m.TLSKeyFile == "") {
+		errs = append(errs, fmt.Errorf("MQTT_TLS_CERT_FILE y MQTT_TLS_KEY_FILE deben configurarse juntos"))
+	}
+	return errs
+}
+
+// parseCrewTokens parses "crew=token" pairs separated by commas.
+func parseCrewTokens(raw string) (map[string]string, error) {
+	tokens := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		crew, token, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || crew == "" || token == "" {
+			return nil, fmt.Errorf("GRPC_CREW_TOKENS debe tener el formato cuadrilla=token separados por coma")
+		}
+		tokens[crew] = token
+	}
+	return tokens, nil
 }
 
 func getEnv(key, defaultValue string) string {
@@ -50,3 +703,39 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvBool(key string, defaultValue bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s debe ser true o false, recibido: %q", key, value)
+	}
+	return b, nil
+}
+
+func getEnvInt(key string, defaultValue int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s debe ser un número entero, recibido: %q", key, value)
+	}
+	return n, nil
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s debe ser una duración como 30s o 5m, recibido: %q", key, value)
+	}
+	return d, nil
+}
@@ -0,0 +1,97 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// CrewIDMetadataKey es la clave de metadatos con el grupo de trabajo que llama.
+	CrewIDMetadataKey = "x-crew-id"
+
+	// AuthorizationMetadataKey lleva "Bearer <token>" con el token de la cuadrilla.
+	AuthorizationMetadataKey = "authorization"
+)
+
+type crewKey struct{}
+
+// CrewFromContext retorna la cuadrilla autenticada por los interceptores.
+func CrewFromContext(ctx context.Context) (string, bool) {
+	crew, ok := ctx.Value(crewKey{}).(string)
+	return crew, ok
+}
+
+// Authenticator valida los tokens por cuadrilla enviados en los metadatos.
+type Authenticator struct {
+	tokens map[string]string
+}
+
+// NewAuthenticator crea un autenticador a partir del token de cada cuadrilla.
+// Un mapa vacío rechaza todas las llamadas.
+func NewAuthenticator(tokens map[string]string) *Authenticator {
+	return &Authenticator{tokens: tokens}
+}
+
+// authenticate retorna ctx con la cuadrilla autenticada, o un error
+// Unauthenticated si falta o no coincide el token.
+func (a *Authenticator) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	crew := first(md, CrewIDMetadataKey)
+	if crew == "" {
+		return nil, status.Error(codes.Unauthenticated, "metadato "+CrewIDMetadataKey+" requerido")
+	}
+
+	provided, ok := strings.CutPrefix(first(md, AuthorizationMetadataKey), "Bearer ")
+	expected, known := a.tokens[crew]
+	if !ok || !known || expected == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(expected)) != 1 {
+		return nil, status.Error(codes.Unauthenticated, "token de cuadrilla inválido o faltante")
+	}
+
+	return context.WithValue(ctx, crewKey{}, crew), nil
+}
+
+// UnaryInterceptor autentica las llamadas unarias.
+func (a *Authenticator) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := a.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor autentica los streams antes de recibir el primer mensaje.
+func (a *Authenticator) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticatedStream expone el contexto con la cuadrilla autenticada.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
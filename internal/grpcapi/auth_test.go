@@ -0,0 +1,100 @@
+package grpcapi
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryInterceptor(t *testing.T) {
+	auth := NewAuthenticator(map[string]string{"G0/CUADRILLA_1": "token-1"})
+
+	tests := []struct {
+		name string
+		md   metadata.MD
+		want codes.Code
+	}{
+		{name: "token válido", md: metadata.Pairs(CrewIDMetadataKey, "G0/CUADRILLA_1", AuthorizationMetadataKey, "Bearer token-1"), want: codes.OK},
+		{name: "sin metadatos", want: codes.Unauthenticated},
+		{name: "sin cuadrilla", md: metadata.Pairs(AuthorizationMetadataKey, "Bearer token-1"), want: codes.Unauthenticated},
+		{name: "token incorrecto", md: metadata.Pairs(CrewIDMetadataKey, "G0/CUADRILLA_1", AuthorizationMetadataKey, "Bearer otro"), want: codes.Unauthenticated},
+		{name: "cuadrilla desconocida", md: metadata.Pairs(CrewIDMetadataKey, "G0/CUADRILLA_2", AuthorizationMetadataKey, "Bearer token-1"), want: codes.Unauthenticated},
+		{name: "sin esquema Bearer", md: metadata.Pairs(CrewIDMetadataKey, "G0/CUADRILLA_1", AuthorizationMetadataKey, "token-1"), want: codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+
+			var crew string
+			handler := func(ctx context.Context, req any) (any, error) {
+				crew, _ = CrewFromContext(ctx)
+				return req, nil
+			}
+
+			_, err := auth.UnaryInterceptor()(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("código = %v; esperado %v", got, tt.want)
+			}
+			if tt.want == codes.OK && crew != "G0/CUADRILLA_1" {
+				t.Errorf("cuadrilla en contexto = %q; esperado G0/CUADRILLA_1", crew)
+			}
+		})
+	}
+}
+
+// streamFalso implementa grpc.ServerStream con un contexto fijo.
+type streamFalso struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *streamFalso) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamInterceptor(t *testing.T) {
+	auth := NewAuthenticator(map[string]string{"G0/CUADRILLA_1": "token-1"})
+
+	t.Run("token válido", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs(CrewIDMetadataKey, "G0/CUADRILLA_1", AuthorizationMetadataKey, "Bearer token-1"))
+
+		var crew string
+		handler := func(_ any, ss grpc.ServerStream) error {
+			crew, _ = CrewFromContext(ss.Context())
+			return nil
+		}
+		if err := auth.StreamInterceptor()(nil, &streamFalso{ctx: ctx}, &grpc.StreamServerInfo{}, handler); err != nil {
+			t.Fatalf("error inesperado: %v", err)
+		}
+		if crew != "G0/CUADRILLA_1" {
+			t.Errorf("cuadrilla en contexto = %q; esperado G0/CUADRILLA_1", crew)
+		}
+	})
+
+	t.Run("token incorrecto", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs(CrewIDMetadataKey, "G0/CUADRILLA_1", AuthorizationMetadataKey, "Bearer otro"))
+
+		called := false
+		handler := func(_ any, _ grpc.ServerStream) error {
+			called = true
+			return nil
+		}
+		err := auth.StreamInterceptor()(nil, &streamFalso{ctx: ctx}, &grpc.StreamServerInfo{}, handler)
+		if status.Code(err) != codes.Unauthenticated {
+			t.Errorf("código = %v; esperado Unauthenticated", status.Code(err))
+		}
+		if called {
+			t.Error("el handler no debe ejecutarse sin autenticación")
+		}
+	})
+}
@@ -0,0 +1,161 @@
+// Package grpcapi implementa el servicio gRPC de ingesta para clientes de
+// telemetría de alta frecuencia que prefieren un stream persistente a una
+// solicitud HTTPS por punto.
+package grpcapi
+
+//go:generate protoc -I ../../proto --go_out=../.. --go_opt=module=github.com/120m4n/GridFlow-Dynamics --go-grpc_out=../.. --go-grpc_opt=module=github.com/120m4n/GridFlow-Dynamics tracking/v1/tracking.proto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/120m4n/GridFlow-Dynamics/internal/grpcapi/trackingpb"
+	"github.com/120m4n/GridFlow-Dynamics/internal/ingest"
+	"github.com/120m4n/GridFlow-Dynamics/internal/messaging"
+	"github.com/120m4n/GridFlow-Dynamics/pkg/domain"
+)
+
+// TrackingServer implementa trackingpb.TrackingServiceServer sobre el mismo
+// servicio de ingesta que el handler HTTP.
+type TrackingServer struct {
+	trackingpb.UnimplementedTrackingServiceServer
+
+	service *ingest.Service
+	logger  *slog.Logger
+}
+
+// NewTrackingServer crea la implementación del servicio de tracking.
+func NewTrackingServer(service *ingest.Service, logger *slog.Logger) *TrackingServer {
+	return &TrackingServer{service: service, logger: logger}
+}
+
+// NewServer crea un servidor gRPC con el servicio de tracking registrado y
+// la autenticación por cuadrilla en sus interceptores.
+func NewServer(service *ingest.Service, auth *Authenticator, logger *slog.Logger, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(auth.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(auth.StreamInterceptor()),
+	)
+	srv := grpc.NewServer(opts...)
+	trackingpb.RegisterTrackingServiceServer(srv, NewTrackingServer(service, logger))
+	return srv
+}
+
+// Shutdown detiene srv esperando a que terminen las llamadas en curso; si ctx
+// expira antes, las cancela.
+func Shutdown(ctx context.Context, srv *grpc.Server) error {
+	done := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		srv.Stop()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// SubmitTracking valida y publica un único mensaje.
+func (s *TrackingServer) SubmitTracking(ctx context.Context, payload *trackingpb.TrackingPayload) (*trackingpb.TrackingResponse, error) {
+	if err := s.submit(ctx, payload); err != nil {
+		return nil, err
+	}
+	return &trackingpb.TrackingResponse{
+		Status:  "success",
+		Message: "Mensaje de inventario de cuadrilla recibido correctamente.",
+	}, nil
+}
+
+// StreamTracking publica cada mensaje recibido hasta que el cliente cierra el
+// stream. Los mensajes inválidos o limitados se cuentan como rechazados; la
+// falta de conexión a NATS o un fallo al publicar corta el stream con Unavailable.
+func (s *TrackingServer) StreamTracking(stream trackingpb.TrackingService_StreamTrackingServer) error {
+	var aceptados, rechazados int64
+	for {
+		payload, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(&trackingpb.TrackingResponse{
+				Status:     "success",
+				Message:    fmt.Sprintf("%d mensajes aceptados, %d rechazados", aceptados, rechazados),
+				Aceptados:  aceptados,
+				Rechazados: rechazados,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		err = s.submit(stream.Context(), payload)
+		switch status.Code(err) {
+		case codes.OK:
+			aceptados++
+		case codes.InvalidArgument, codes.ResourceExhausted:
+			rechazados++
+		default:
+			return err
+		}
+	}
+}
+
+// submit aplica la ingesta a un mensaje y traduce sus errores a estados gRPC.
+func (s *TrackingServer) submit(ctx context.Context, payload *trackingpb.TrackingPayload) error {
+	crew, _ := CrewFromContext(ctx)
+	mensaje := toMensaje(payload)
+	if mensaje.GrupoTrabajo != crew {
+		return status.Errorf(codes.PermissionDenied, "grupoTrabajo %q no corresponde a la cuadrilla autenticada", mensaje.GrupoTrabajo)
+	}
+
+	err := s.service.Submit(ctx, crew, mensaje)
+
+	var validationErr *ingest.ValidationError
+	var publishErr *ingest.PublishError
+	switch {
+	case err == nil:
+		s.logger.Debug("Mensaje de inventario recibido por gRPC", "grupo_trabajo", crew, "codigo_odt", mensaje.CodigoODT)
+		return nil
+	case errors.As(err, &validationErr):
+		s.logger.Warn("Mensaje gRPC rechazado: payload inválido", "grupo_trabajo", crew, "error", err)
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, messaging.ErrNotConnected), errors.Is(err, messaging.ErrPublishTimeout), errors.Is(err, messaging.ErrCircuitOpen):
+		return status.Error(codes.Unavailable, "Servicio de mensajería no disponible; reintente más tarde")
+	case errors.Is(err, ingest.ErrRateLimited):
+		return status.Error(codes.ResourceExhausted, "Rate limit excedido")
+	case errors.As(err, &publishErr):
+		s.logger.Error("Fallo al publicar evento de inventario", "grupo_trabajo", crew, "error", publishErr.Err)
+		if errors.Is(err, messaging.ErrMarshal) {
+			return status.Error(codes.Internal, "Fallo al serializar el evento de inventario")
+		}
+		return status.Error(codes.Unavailable, "Fallo al procesar mensaje de inventario")
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// toMensaje convierte el payload gRPC al mensaje del dominio.
+func toMensaje(p *trackingpb.TrackingPayload) *domain.MensajeInventarioCuadrilla {
+	mensaje := &domain.MensajeInventarioCuadrilla{
+		GrupoTrabajo:       p.GetGrupoTrabajo(),
+		NombreEmpleado:     p.GetNombreEmpleado(),
+		Coordenadas:        domain.Coordenadas{Latitud: p.GetCoordenadas().GetLatitud(), Longitud: p.GetCoordenadas().GetLongitud()},
+		CodigoODT:          p.GetCodigoOdt(),
+		Estado:             p.GetEstado(),
+		PorcentajeProgreso: int(p.GetPorcentajeProgreso()),
+		NivelBateria:       int(p.GetNivelBateria()),
+	}
+	if p.GetTimestamp() != nil {
+		mensaje.Timestamp = p.GetTimestamp().AsTime()
+	}
+	return mensaje
+}
@@ -0,0 +1,198 @@
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/120m4n/GridFlow-Dynamics/internal/grpcapi/trackingpb"
+	"github.com/120m4n/GridFlow-Dynamics/internal/ingest"
+	"github.com/120m4n/GridFlow-Dynamics/internal/messaging"
+	"github.com/120m4n/GridFlow-Dynamics/internal/messaging/natstest"
+	"github.com/120m4n/GridFlow-Dynamics/internal/ratelimit"
+	"github.com/120m4n/GridFlow-Dynamics/pkg/domain"
+)
+
+const (
+	subjectPrueba = "inventario.cuadrilla"
+	crewPrueba    = "G0/CUADRILLA_1"
+	tokenPrueba   = "token-1"
+)
+
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// iniciarServidor sirve el servicio de tracking sobre bufconn y retorna un
+// cliente conectado a él.
+func iniciarServidor(t *testing.T, publishers messaging.PublisherProvider, limit int) trackingpb.TrackingServiceClient {
+	t.Helper()
+	service := ingest.NewService(publishers, ratelimit.New(limit, time.Minute)).WithSubject(subjectPrueba)
+	srv := NewServer(service, NewAuthenticator(map[string]string{crewPrueba: tokenPrueba}), testLogger)
+
+	ln := bufconn.Listen(1 << 20)
+	go srv.Serve(ln)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Error al conectar al servidor gRPC: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return trackingpb.NewTrackingServiceClient(conn)
+}
+
+func contextoAutenticado(crew, token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(),
+		CrewIDMetadataKey, crew, AuthorizationMetadataKey, "Bearer "+token)
+}
+
+func payloadValido(grupo string) *trackingpb.TrackingPayload {
+	return &trackingpb.TrackingPayload{
+		GrupoTrabajo:       grupo,
+		NombreEmpleado:     "Juan Perez",
+		Timestamp:          timestamppb.New(time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)),
+		Coordenadas:        &trackingpb.Coordenadas{Latitud: 4.6097, Longitud: -74.0817},
+		CodigoOdt:          "ODT-001",
+		Estado:             "trabajando",
+		PorcentajeProgreso: 50,
+		NivelBateria:       80,
+	}
+}
+
+func TestSubmitTrackingPublica(t *testing.T) {
+	publisher, sub := natstest.Subscribed(t, subjectPrueba)
+	client := iniciarServidor(t, publisher, 100)
+
+	resp, err := client.SubmitTracking(contextoAutenticado(crewPrueba, tokenPrueba), payloadValido(crewPrueba))
+	if err != nil {
+		t.Fatalf("SubmitTracking error: %v", err)
+	}
+	if resp.GetStatus() != "success" {
+		t.Errorf("Status = %q; esperado success", resp.GetStatus())
+	}
+
+	msg, err := sub.NextMsg(2 * time.Second)
+	if err != nil {
+		t.Fatalf("No se recibió el evento en NATS: %v", err)
+	}
+	var evento domain.EventoInventarioCuadrilla
+	if err := json.Unmarshal(msg.Data, &evento); err != nil {
+		t.Fatalf("Evento inválido: %v", err)
+	}
+	if evento.GrupoTrabajo != crewPrueba || evento.CodigoODT != "ODT-001" || evento.PorcentajeProgreso != 50 {
+		t.Errorf("Evento = %+v; no corresponde al payload enviado", evento)
+	}
+	if !evento.Timestamp.Equal(time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)) {
+		t.Errorf("Timestamp = %v; esperado 2024-01-15T10:30:00Z", evento.Timestamp)
+	}
+}
+
+func TestSubmitTrackingErrores(t *testing.T) {
+	publisher, _ := natstest.Subscribed(t, subjectPrueba)
+	client := iniciarServidor(t, publisher, 1)
+
+	invalido := payloadValido(crewPrueba)
+	invalido.Estado = "invalido"
+
+	tests := []struct {
+		nombre  string
+		ctx     context.Context
+		payload *trackingpb.TrackingPayload
+		want    codes.Code
+	}{
+		{nombre: "sin token", ctx: context.Background(), payload: payloadValido(crewPrueba), want: codes.Unauthenticated},
+		{nombre: "token de otra cuadrilla", ctx: contextoAutenticado(crewPrueba, "otro"), payload: payloadValido(crewPrueba), want: codes.Unauthenticated},
+		{nombre: "payload de otra cuadrilla", ctx: contextoAutenticado(crewPrueba, tokenPrueba), payload: payloadValido("G0/CUADRILLA_2"), want: codes.PermissionDenied},
+		{nombre: "payload inválido", ctx: contextoAutenticado(crewPrueba, tokenPrueba), payload: invalido, want: codes.InvalidArgument},
+		{nombre: "primero dentro del límite", ctx: contextoAutenticado(crewPrueba, tokenPrueba), payload: payloadValido(crewPrueba), want: codes.OK},
+		{nombre: "rate limit excedido", ctx: contextoAutenticado(crewPrueba, tokenPrueba), payload: payloadValido(crewPrueba), want: codes.ResourceExhausted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			_, err := client.SubmitTracking(tt.ctx, tt.payload)
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("código = %v; esperado %v (error: %v)", got, tt.want, err)
+			}
+		})
+	}
+}
+
+func TestStreamTracking(t *testing.T) {
+	publisher, sub := natstest.Subscribed(t, subjectPrueba)
+	client := iniciarServidor(t, publisher, 100)
+
+	stream, err := client.StreamTracking(contextoAutenticado(crewPrueba, tokenPrueba))
+	if err != nil {
+		t.Fatalf("StreamTracking error: %v", err)
+	}
+
+	invalido := payloadValido(crewPrueba)
+	invalido.NivelBateria = 150
+	for _, payload := range []*trackingpb.TrackingPayload{payloadValido(crewPrueba), invalido, payloadValido(crewPrueba)} {
+		if err := stream.Send(payload); err != nil {
+			t.Fatalf("Send error: %v", err)
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatalf("CloseAndRecv error: %v", err)
+	}
+	if resp.GetAceptados() != 2 || resp.GetRechazados() != 1 {
+		t.Errorf("aceptados = %d, rechazados = %d; esperado 2 y 1", resp.GetAceptados(), resp.GetRechazados())
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := sub.NextMsg(2 * time.Second); err != nil {
+			t.Fatalf("Evento %d no recibido en NATS: %v", i+1, err)
+		}
+	}
+}
+
+func TestSubmitTrackingSinNATS(t *testing.T) {
+	client := iniciarServidor(t, nil, 100)
+
+	_, err := client.SubmitTracking(contextoAutenticado(crewPrueba, tokenPrueba), payloadValido(crewPrueba))
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("código = %v; esperado Unavailable", status.Code(err))
+	}
+}
+
+func TestStreamTrackingSinAutenticacion(t *testing.T) {
+	client := iniciarServidor(t, nil, 100)
+
+	stream, err := client.StreamTracking(context.Background())
+	if err != nil {
+		t.Fatalf("StreamTracking error: %v", err)
+	}
+	stream.Send(payloadValido(crewPrueba))
+	if _, err := stream.CloseAndRecv(); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("código = %v; esperado Unauthenticated", status.Code(err))
+	}
+}
+
+func TestShutdownSinLlamadas(t *testing.T) {
+	srv := grpc.NewServer()
+	ln := bufconn.Listen(1 << 20)
+	go srv.Serve(ln)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := Shutdown(ctx, srv); err != nil {
+		t.Errorf("Shutdown error: %v", err)
+	}
+}
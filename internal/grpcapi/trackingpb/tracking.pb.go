@@ -0,0 +1,416 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        (unknown)
+// source: tracking/v1/tracking.proto
+
+package trackingpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// TrackingPayload equivale al JSON de POST /api/v1/mensaje_inventario/cuadrilla
+// y se valida con las mismas reglas.
+type TrackingPayload struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GrupoTrabajo       string                 `protobuf:"bytes,1,opt,name=grupo_trabajo,json=grupoTrabajo,proto3" json:"grupo_trabajo,omitempty"`
+	NombreEmpleado     string                 `protobuf:"bytes,2,opt,name=nombre_empleado,json=nombreEmpleado,proto3" json:"nombre_empleado,omitempty"`
+	Timestamp          *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Coordenadas        *Coordenadas           `protobuf:"bytes,4,opt,name=coordenadas,proto3" json:"coordenadas,omitempty"`
+	CodigoOdt          string                 `protobuf:"bytes,5,opt,name=codigo_odt,json=codigoOdt,proto3" json:"codigo_odt,omitempty"`
+	Estado             string                 `protobuf:"bytes,6,opt,name=estado,proto3" json:"estado,omitempty"`
+	PorcentajeProgreso int32                  `protobuf:"varint,7,opt,name=porcentaje_progreso,json=porcentajeProgreso,proto3" json:"porcentaje_progreso,omitempty"`
+	NivelBateria       int32                  `protobuf:"varint,8,opt,name=nivel_bateria,json=nivelBateria,proto3" json:"nivel_bateria,omitempty"`
+}
+
+func (x *TrackingPayload) Reset() {
+	*x = TrackingPayload{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tracking_v1_tracking_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TrackingPayload) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackingPayload) ProtoMessage() {}
+
+func (x *TrackingPayload) ProtoReflect() protoreflect.Message {
+	mi := &file_tracking_v1_tracking_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackingPayload.ProtoReflect.Descriptor instead.
+func (*TrackingPayload) Descriptor() ([]byte, []int) {
+	return file_tracking_v1_tracking_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TrackingPayload) GetGrupoTrabajo() string {
+	if x != nil {
+		return x.GrupoTrabajo
+	}
+	return ""
+}
+
+func (x *TrackingPayload) GetNombreEmpleado() string {
+	if x != nil {
+		return x.NombreEmpleado
+	}
+	return ""
+}
+
+func (x *TrackingPayload) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *TrackingPayload) GetCoordenadas() *Coordenadas {
+	if x != nil {
+		return x.Coordenadas
+	}
+	return nil
+}
+
+func (x *TrackingPayload) GetCodigoOdt() string {
+	if x != nil {
+		return x.CodigoOdt
+	}
+	return ""
+}
+
+func (x *TrackingPayload) GetEstado() string {
+	if x != nil {
+		return x.Estado
+	}
+	return ""
+}
+
+func (x *TrackingPayload) GetPorcentajeProgreso() int32 {
+	if x != nil {
+		return x.PorcentajeProgreso
+	}
+	return 0
+}
+
+func (x *TrackingPayload) GetNivelBateria() int32 {
+	if x != nil {
+		return x.NivelBateria
+	}
+	return 0
+}
+
+// Coordenadas representa los datos de ubicación GPS.
+type Coordenadas struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Latitud  float64 `protobuf:"fixed64,1,opt,name=latitud,proto3" json:"latitud,omitempty"`
+	Longitud float64 `protobuf:"fixed64,2,opt,name=longitud,proto3" json:"longitud,omitempty"`
+}
+
+func (x *Coordenadas) Reset() {
+	*x = Coordenadas{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tracking_v1_tracking_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Coordenadas) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Coordenadas) ProtoMessage() {}
+
+func (x *Coordenadas) ProtoReflect() protoreflect.Message {
+	mi := &file_tracking_v1_tracking_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Coordenadas.ProtoReflect.Descriptor instead.
+func (*Coordenadas) Descriptor() ([]byte, []int) {
+	return file_tracking_v1_tracking_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Coordenadas) GetLatitud() float64 {
+	if x != nil {
+		return x.Latitud
+	}
+	return 0
+}
+
+func (x *Coordenadas) GetLongitud() float64 {
+	if x != nil {
+		return x.Longitud
+	}
+	return 0
+}
+
+// TrackingResponse confirma la recepción. Aceptados y rechazados solo se
+// informan en StreamTracking.
+type TrackingResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status     string `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Message    string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Aceptados  int64  `protobuf:"varint,3,opt,name=aceptados,proto3" json:"aceptados,omitempty"`
+	Rechazados int64  `protobuf:"varint,4,opt,name=rechazados,proto3" json:"rechazados,omitempty"`
+}
+
+func (x *TrackingResponse) Reset() {
+	*x = TrackingResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tracking_v1_tracking_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TrackingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackingResponse) ProtoMessage() {}
+
+func (x *TrackingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tracking_v1_tracking_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackingResponse.ProtoReflect.Descriptor instead.
+func (*TrackingResponse) Descriptor() ([]byte, []int) {
+	return file_tracking_v1_tracking_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TrackingResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TrackingResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *TrackingResponse) GetAceptados() int64 {
+	if x != nil {
+		return x.Aceptados
+	}
+	return 0
+}
+
+func (x *TrackingResponse) GetRechazados() int64 {
+	if x != nil {
+		return x.Rechazados
+	}
+	return 0
+}
+
+var File_tracking_v1_tracking_proto protoreflect.FileDescriptor
+
+var file_tracking_v1_tracking_proto_rawDesc = []byte{
+	0x0a, 0x1a, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x2f, 0x76, 0x31, 0x2f, 0x74, 0x72,
+	0x61, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x14, 0x67, 0x72,
+	0x69, 0x64, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x2e,
+	0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x22, 0xeb, 0x02, 0x0a, 0x0f, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x69, 0x6e, 0x67,
+	0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x67, 0x72, 0x75, 0x70, 0x6f,
+	0x5f, 0x74, 0x72, 0x61, 0x62, 0x61, 0x6a, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
+	0x67, 0x72, 0x75, 0x70, 0x6f, 0x54, 0x72, 0x61, 0x62, 0x61, 0x6a, 0x6f, 0x12, 0x27, 0x0a, 0x0f,
+	0x6e, 0x6f, 0x6d, 0x62, 0x72, 0x65, 0x5f, 0x65, 0x6d, 0x70, 0x6c, 0x65, 0x61, 0x64, 0x6f, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x6e, 0x6f, 0x6d, 0x62, 0x72, 0x65, 0x45, 0x6d, 0x70,
+	0x6c, 0x65, 0x61, 0x64, 0x6f, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12,
+	0x43, 0x0a, 0x0b, 0x63, 0x6f, 0x6f, 0x72, 0x64, 0x65, 0x6e, 0x61, 0x64, 0x61, 0x73, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x67, 0x72, 0x69, 0x64, 0x66, 0x6c, 0x6f, 0x77, 0x2e,
+	0x74, 0x72, 0x61, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6f, 0x72,
+	0x64, 0x65, 0x6e, 0x61, 0x64, 0x61, 0x73, 0x52, 0x0b, 0x63, 0x6f, 0x6f, 0x72, 0x64, 0x65, 0x6e,
+	0x61, 0x64, 0x61, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6f, 0x64, 0x69, 0x67, 0x6f, 0x5f, 0x6f,
+	0x64, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6f, 0x64, 0x69, 0x67, 0x6f,
+	0x4f, 0x64, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x73, 0x74, 0x61, 0x64, 0x6f, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x65, 0x73, 0x74, 0x61, 0x64, 0x6f, 0x12, 0x2f, 0x0a, 0x13, 0x70,
+	0x6f, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x6a, 0x65, 0x5f, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65,
+	0x73, 0x6f, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x12, 0x70, 0x6f, 0x72, 0x63, 0x65, 0x6e,
+	0x74, 0x61, 0x6a, 0x65, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x6f, 0x12, 0x23, 0x0a, 0x0d,
+	0x6e, 0x69, 0x76, 0x65, 0x6c, 0x5f, 0x62, 0x61, 0x74, 0x65, 0x72, 0x69, 0x61, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0c, 0x6e, 0x69, 0x76, 0x65, 0x6c, 0x42, 0x61, 0x74, 0x65, 0x72, 0x69,
+	0x61, 0x22, 0x43, 0x0a, 0x0b, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x65, 0x6e, 0x61, 0x64, 0x61, 0x73,
+	0x12, 0x18, 0x0a, 0x07, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x07, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x6f,
+	0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x6c, 0x6f,
+	0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x22, 0x82, 0x01, 0x0a, 0x10, 0x54, 0x72, 0x61, 0x63, 0x6b,
+	0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1c, 0x0a,
+	0x09, 0x61, 0x63, 0x65, 0x70, 0x74, 0x61, 0x64, 0x6f, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x09, 0x61, 0x63, 0x65, 0x70, 0x74, 0x61, 0x64, 0x6f, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x72,
+	0x65, 0x63, 0x68, 0x61, 0x7a, 0x61, 0x64, 0x6f, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0a, 0x72, 0x65, 0x63, 0x68, 0x61, 0x7a, 0x61, 0x64, 0x6f, 0x73, 0x32, 0xd5, 0x01, 0x0a, 0x0f,
+	0x54, 0x72, 0x61, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12,
+	0x5f, 0x0a, 0x0e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x69, 0x6e,
+	0x67, 0x12, 0x25, 0x2e, 0x67, 0x72, 0x69, 0x64, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x74, 0x72, 0x61,
+	0x63, 0x6b, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x69, 0x6e,
+	0x67, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x1a, 0x26, 0x2e, 0x67, 0x72, 0x69, 0x64, 0x66,
+	0x6c, 0x6f, 0x77, 0x2e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e,
+	0x54, 0x72, 0x61, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x61, 0x0a, 0x0e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x69,
+	0x6e, 0x67, 0x12, 0x25, 0x2e, 0x67, 0x72, 0x69, 0x64, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x74, 0x72,
+	0x61, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x69,
+	0x6e, 0x67, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x1a, 0x26, 0x2e, 0x67, 0x72, 0x69, 0x64,
+	0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31,
+	0x2e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x28, 0x01, 0x42, 0x41, 0x5a, 0x3f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x31, 0x32, 0x30, 0x6d, 0x34, 0x6e, 0x2f, 0x47, 0x72, 0x69, 0x64, 0x46, 0x6c, 0x6f,
+	0x77, 0x2d, 0x44, 0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63, 0x73, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x61, 0x70, 0x69, 0x2f, 0x74, 0x72, 0x61, 0x63,
+	0x6b, 0x69, 0x6e, 0x67, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_tracking_v1_tracking_proto_rawDescOnce sync.Once
+	file_tracking_v1_tracking_proto_rawDescData = file_tracking_v1_tracking_proto_rawDesc
+)
+
+func file_tracking_v1_tracking_proto_rawDescGZIP() []byte {
+	file_tracking_v1_tracking_proto_rawDescOnce.Do(func() {
+		file_tracking_v1_tracking_proto_rawDescData = protoimpl.X.CompressGZIP(file_tracking_v1_tracking_proto_rawDescData)
+	})
+	return file_tracking_v1_tracking_proto_rawDescData
+}
+
+var file_tracking_v1_tracking_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_tracking_v1_tracking_proto_goTypes = []interface{}{
+	(*TrackingPayload)(nil),       // 0: gridflow.tracking.v1.TrackingPayload
+	(*Coordenadas)(nil),           // 1: gridflow.tracking.v1.Coordenadas
+	(*TrackingResponse)(nil),      // 2: gridflow.tracking.v1.TrackingResponse
+	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
+}
+var file_tracking_v1_tracking_proto_depIdxs = []int32{
+	3, // 0: gridflow.tracking.v1.TrackingPayload.timestamp:type_name -> google.protobuf.Timestamp
+	1, // 1: gridflow.tracking.v1.TrackingPayload.coordenadas:type_name -> gridflow.tracking.v1.Coordenadas
+	0, // 2: gridflow.tracking.v1.TrackingService.SubmitTracking:input_type -> gridflow.tracking.v1.TrackingPayload
+	0, // 3: gridflow.tracking.v1.TrackingService.StreamTracking:input_type -> gridflow.tracking.v1.TrackingPayload
+	2, // 4: gridflow.tracking.v1.TrackingService.SubmitTracking:output_type -> gridflow.tracking.v1.TrackingResponse
+	2, // 5: gridflow.tracking.v1.TrackingService.StreamTracking:output_type -> gridflow.tracking.v1.TrackingResponse
+	4, // [4:6] is the sub-list for method output_type
+	2, // [2:4] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_tracking_v1_tracking_proto_init() }
+func file_tracking_v1_tracking_proto_init() {
+	if File_tracking_v1_tracking_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_tracking_v1_tracking_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TrackingPayload); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tracking_v1_tracking_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Coordenadas); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tracking_v1_tracking_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TrackingResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_tracking_v1_tracking_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_tracking_v1_tracking_proto_goTypes,
+		DependencyIndexes: file_tracking_v1_tracking_proto_depIdxs,
+		MessageInfos:      file_tracking_v1_tracking_proto_msgTypes,
+	}.Build()
+	File_tracking_v1_tracking_proto = out.File
+	file_tracking_v1_tracking_proto_rawDesc = nil
+	file_tracking_v1_tracking_proto_goTypes = nil
+	file_tracking_v1_tracking_proto_depIdxs = nil
+}
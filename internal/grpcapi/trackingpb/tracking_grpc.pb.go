@@ -0,0 +1,189 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: tracking/v1/tracking.proto
+
+package trackingpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	TrackingService_SubmitTracking_FullMethodName = "/gridflow.tracking.v1.TrackingService/SubmitTracking"
+	TrackingService_StreamTracking_FullMethodName = "/gridflow.tracking.v1.TrackingService/StreamTracking"
+)
+
+// TrackingServiceClient is the client API for TrackingService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TrackingServiceClient interface {
+	// SubmitTracking valida y publica un único mensaje.
+	SubmitTracking(ctx context.Context, in *TrackingPayload, opts ...grpc.CallOption) (*TrackingResponse, error)
+	// StreamTracking recibe mensajes sobre un stream persistente y responde al
+	// cerrarlo el cliente. Los mensajes inválidos o que exceden el rate limit se
+	// cuentan como rechazados sin cortar el stream.
+	StreamTracking(ctx context.Context, opts ...grpc.CallOption) (TrackingService_StreamTrackingClient, error)
+}
+
+type trackingServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTrackingServiceClient(cc grpc.ClientConnInterface) TrackingServiceClient {
+	return &trackingServiceClient{cc}
+}
+
+func (c *trackingServiceClient) SubmitTracking(ctx context.Context, in *TrackingPayload, opts ...grpc.CallOption) (*TrackingResponse, error) {
+	out := new(TrackingResponse)
+	err := c.cc.Invoke(ctx, TrackingService_SubmitTracking_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackingServiceClient) StreamTracking(ctx context.Context, opts ...grpc.CallOption) (TrackingService_StreamTrackingClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TrackingService_ServiceDesc.Streams[0], TrackingService_StreamTracking_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &trackingServiceStreamTrackingClient{stream}
+	return x, nil
+}
+
+type TrackingService_StreamTrackingClient interface {
+	Send(*TrackingPayload) error
+	CloseAndRecv() (*TrackingResponse, error)
+	grpc.ClientStream
+}
+
+type trackingServiceStreamTrackingClient struct {
+	grpc.ClientStream
+}
+
+func (x *trackingServiceStreamTrackingClient) Send(m *TrackingPayload) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *trackingServiceStreamTrackingClient) CloseAndRecv() (*TrackingResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(TrackingResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TrackingServiceServer is the server API for TrackingService service.
+// All implementations must embed UnimplementedTrackingServiceServer
+// for forward compatibility
+type TrackingServiceServer interface {
+	// SubmitTracking valida y publica un único mensaje.
+	SubmitTracking(context.Context, *TrackingPayload) (*TrackingResponse, error)
+	// StreamTracking recibe mensajes sobre un stream persistente y responde al
+	// cerrarlo el cliente. Los mensajes inválidos o que exceden el rate limit se
+	// cuentan como rechazados sin cortar el stream.
+	StreamTracking(TrackingService_StreamTrackingServer) error
+	mustEmbedUnimplementedTrackingServiceServer()
+}
+
+// UnimplementedTrackingServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedTrackingServiceServer struct {
+}
+
+func (UnimplementedTrackingServiceServer) SubmitTracking(context.Context, *TrackingPayload) (*TrackingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitTracking not implemented")
+}
+func (UnimplementedTrackingServiceServer) StreamTracking(TrackingService_StreamTrackingServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamTracking not implemented")
+}
+func (UnimplementedTrackingServiceServer) mustEmbedUnimplementedTrackingServiceServer() {}
+
+// UnsafeTrackingServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TrackingServiceServer will
+// result in compilation errors.
+type UnsafeTrackingServiceServer interface {
+	mustEmbedUnimplementedTrackingServiceServer()
+}
+
+func RegisterTrackingServiceServer(s grpc.ServiceRegistrar, srv TrackingServiceServer) {
+	s.RegisterService(&TrackingService_ServiceDesc, srv)
+}
+
+func _TrackingService_SubmitTracking_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TrackingPayload)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackingServiceServer).SubmitTracking(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackingService_SubmitTracking_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackingServiceServer).SubmitTracking(ctx, req.(*TrackingPayload))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrackingService_StreamTracking_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TrackingServiceServer).StreamTracking(&trackingServiceStreamTrackingServer{stream})
+}
+
+type TrackingService_StreamTrackingServer interface {
+	SendAndClose(*TrackingResponse) error
+	Recv() (*TrackingPayload, error)
+	grpc.ServerStream
+}
+
+type trackingServiceStreamTrackingServer struct {
+	grpc.ServerStream
+}
+
+func (x *trackingServiceStreamTrackingServer) SendAndClose(m *TrackingResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *trackingServiceStreamTrackingServer) Recv() (*TrackingPayload, error) {
+	m := new(TrackingPayload)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TrackingService_ServiceDesc is the grpc.ServiceDesc for TrackingService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TrackingService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gridflow.tracking.v1.TrackingService",
+	HandlerType: (*TrackingServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SubmitTracking",
+			Handler:    _TrackingService_SubmitTracking_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamTracking",
+			Handler:       _TrackingService_StreamTracking_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "tracking/v1/tracking.proto",
+}
@@ -0,0 +1,115 @@
+// Package ingest contiene la lógica de ingesta compartida por los transportes
+// HTTP y gRPC: validación, rate limit por cuadrilla y publicación a NATS.
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/120m4n/GridFlow-Dynamics/internal/messaging"
+	"github.com/120m4n/GridFlow-Dynamics/internal/ratelimit"
+	"github.com/120m4n/GridFlow-Dynamics/pkg/domain"
+)
+
+// ErrRateLimited indica que la cuadrilla excedió su límite de solicitudes.
+var ErrRateLimited = errors.New("rate limit excedido")
+
+// ValidationError indica que el mensaje no pasó las reglas de validación del
+// dominio. Su texto es el del error de validación original.
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// PublishError indica que el evento válido no pudo publicarse a NATS.
+type PublishError struct {
+	Err error
+}
+
+func (e *PublishError) Error() string {
+	return fmt.Sprintf("fallo al publicar evento de inventario: %v", e.Err)
+}
+
+func (e *PublishError) Unwrap() error {
+	return e.Err
+}
+
+// Service valida, limita y publica mensajes de inventario de cuadrilla.
+type Service struct {
+	publishers     messaging.PublisherProvider
+	rateLimiter    *ratelimit.Limiter
+	subject        string
+	publishTimeout time.Duration
+}
+
+// NewService crea el servicio de ingesta. El publisher se consulta en cada
+// mensaje, de modo que la conexión a NATS puede establecerse después.
+func NewService(publishers messaging.PublisherProvider, rateLimiter *ratelimit.Limiter) *Service {
+	return &Service{
+		publishers:     publishers,
+		rateLimiter:    rateLimiter,
+		subject:        messaging.SubjectInventarioCuadrilla,
+		publishTimeout: 5 * time.Second,
+	}
+}
+
+// WithSubject configura el subject NATS donde se publican los eventos de inventario.
+func (s *Service) WithSubject(subject string) *Service {
+	s.subject = subject
+	return s
+}
+
+// Submit valida el mensaje, consume una solicitud del límite de key y publica
+// el evento. Retorna *ValidationError, messaging.ErrNotConnected,
+// ErrRateLimited o *PublishError según el paso que falle; PublishError
+// envuelve los errores tipados de messaging. Si NATS no está disponible
+// (ver Unavailable) el mensaje no se acepta ni consume el límite, para que
+// el cliente reintente sin agotar su cuota.
+func (s *Service) Submit(ctx context.Context, key string, mensaje *domain.MensajeInventarioCuadrilla) error {
+	if err := mensaje.Validar(); err != nil {
+		return &ValidationError{Err: err}
+	}
+
+	publisher := s.currentPublisher()
+	if publisher == nil {
+		return messaging.ErrNotConnected
+	}
+
+	if !s.rateLimiter.Allow(key) {
+		return ErrRateLimited
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.publishTimeout)
+	defer cancel()
+	if err := publisher.Publish(ctx, s.subject, mensaje.Evento(time.Now())); err != nil {
+		if Unavailable(err) {
+			s.rateLimiter.Refund(key)
+		}
+		return &PublishError{Err: err}
+	}
+	return nil
+}
+
+// Unavailable indica si err de Submit es una falla transitoria de NATS ante
+// la que el cliente debe reintentar el mismo mensaje.
+func Unavailable(err error) bool {
+	return errors.Is(err, messaging.ErrNotConnected) ||
+		errors.Is(err, messaging.ErrPublishTimeout) ||
+		errors.Is(err, messaging.ErrCircuitOpen)
+}
+
+func (s *Service) currentPublisher() *messaging.Publisher {
+	if s.publishers == nil {
+		return nil
+	}
+	return s.publishers.Publisher()
+}
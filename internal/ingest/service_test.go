@@ -0,0 +1,103 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/120m4n/GridFlow-Dynamics/internal/messaging"
+	"github.com/120m4n/GridFlow-Dynamics/internal/messaging/natstest"
+	"github.com/120m4n/GridFlow-Dynamics/internal/ratelimit"
+	"github.com/120m4n/GridFlow-Dynamics/pkg/domain"
+)
+
+func mensajeValido() *domain.MensajeInventarioCuadrilla {
+	return &domain.MensajeInventarioCuadrilla{
+		GrupoTrabajo:       "G0/TEST",
+		NombreEmpleado:     "Juan Perez",
+		Timestamp:          time.Now(),
+		Coordenadas:        domain.Coordenadas{Latitud: 4.6, Longitud: -74.0},
+		CodigoODT:          "ODT-001",
+		Estado:             "trabajando",
+		PorcentajeProgreso: 50,
+		NivelBateria:       80,
+	}
+}
+
+func TestSubmitValidacion(t *testing.T) {
+	service := NewService(nil, ratelimit.New(100, time.Minute))
+
+	mensaje := mensajeValido()
+	mensaje.Estado = "invalido"
+
+	err := service.Submit(context.Background(), mensaje.GrupoTrabajo, mensaje)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Submit() = %v; esperado *ValidationError", err)
+	}
+	if err.Error() != mensaje.Validar().Error() {
+		t.Errorf("mensaje = %q; esperado el error de Validar", err.Error())
+	}
+}
+
+func TestSubmitRateLimit(t *testing.T) {
+	service := NewService(natstest.Publisher(t), ratelimit.New(1, time.Minute))
+
+	if err := service.Submit(context.Background(), "G0/TEST", mensajeValido()); err != nil {
+		t.Fatalf("primer Submit() = %v; esperado nil", err)
+	}
+	if err := service.Submit(context.Background(), "G0/TEST", mensajeValido()); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("segundo Submit() = %v; esperado ErrRateLimited", err)
+	}
+	if err := service.Submit(context.Background(), "G0/OTRA", mensajeValido()); err != nil {
+		t.Errorf("Submit() con otra clave = %v; esperado nil", err)
+	}
+}
+
+func TestSubmitInvalidoNoConsumeLimite(t *testing.T) {
+	service := NewService(natstest.Publisher(t), ratelimit.New(1, time.Minute))
+
+	invalido := mensajeValido()
+	invalido.NivelBateria = -1
+	service.Submit(context.Background(), "G0/TEST", invalido)
+
+	if err := service.Submit(context.Background(), "G0/TEST", mensajeValido()); err != nil {
+		t.Errorf("Submit() = %v; un mensaje inválido no debe consumir el límite", err)
+	}
+}
+
+func TestSubmitNoDisponibleNoConsumeLimite(t *testing.T) {
+	rateLimiter := ratelimit.New(1, time.Minute)
+
+	// Con el circuito abierto la publicación falla después de pasar el límite
+	breaker := messaging.NewBreaker(1, time.Minute)
+	breaker.Record(messaging.SubjectInventarioCuadrilla, errors.New("fallo previo"))
+	service := NewService(natstest.Publisher(t, messaging.WithBreaker(breaker)), rateLimiter)
+	for i := 0; i < 3; i++ {
+		if err := service.Submit(context.Background(), "G0/TEST", mensajeValido()); !Unavailable(err) {
+			t.Fatalf("Submit() = %v; esperado ErrCircuitOpen", err)
+		}
+	}
+
+	// Los intentos fallidos se devuelven al límite
+	service = NewService(natstest.Publisher(t), rateLimiter)
+	if err := service.Submit(context.Background(), "G0/TEST", mensajeValido()); err != nil {
+		t.Errorf("Submit() tras recuperarse = %v; esperado nil", err)
+	}
+}
+
+func TestSubmitSinConexion(t *testing.T) {
+	rateLimiter := ratelimit.New(1, time.Minute)
+
+	service := NewService(nil, rateLimiter)
+	if err := service.Submit(context.Background(), "G0/TEST", mensajeValido()); !errors.Is(err, messaging.ErrNotConnected) {
+		t.Fatalf("Submit() = %v; esperado ErrNotConnected", err)
+	}
+
+	// El rechazo por falta de conexión no consume el límite del reintento
+	service = NewService(natstest.Publisher(t), rateLimiter)
+	if err := service.Submit(context.Background(), "G0/TEST", mensajeValido()); err != nil {
+		t.Errorf("Submit() tras reconectar = %v; esperado nil", err)
+	}
+}
@@ -0,0 +1,71 @@
+// Package lifecycle coordinates the ordered shutdown of platform components.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// ErrTimeout is returned for a component that did not stop within its timeout.
+var ErrTimeout = errors.New("tiempo de apagado agotado")
+
+type component struct {
+	name    string
+	timeout time.Duration
+	stop    func(ctx context.Context) error
+}
+
+// Manager stops registered components one at a time, in registration order.
+type Manager struct {
+	logger     *slog.Logger
+	components []component
+}
+
+// NewManager creates an empty lifecycle manager.
+func NewManager(logger *slog.Logger) *Manager {
+	return &Manager{logger: logger}
+}
+
+// Register adds a component to stop after all previously registered ones.
+// stop should return once ctx is done; if it does not, Shutdown moves on.
+func (m *Manager) Register(name string, timeout time.Duration, stop func(ctx context.Context) error) {
+	m.components = append(m.components, component{name: name, timeout: timeout, stop: stop})
+}
+
+// Shutdown stops every component in order, giving each its own timeout. A
+// component that fails or times out is logged and does not prevent the rest
+// from stopping. The returned error joins every failure.
+func (m *Manager) Shutdown() error {
+	var errs []error
+	for _, c := range m.components {
+		start := time.Now()
+		err := stopWithTimeout(c)
+		if err != nil {
+			m.logger.Error("Fallo al detener componente", "componente", c.name, "duracion", time.Since(start), "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", c.name, err))
+			continue
+		}
+		m.logger.Info("Componente detenido", "componente", c.name, "duracion", time.Since(start))
+	}
+	return errors.Join(errs...)
+}
+
+func stopWithTimeout(c component) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.stop(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ErrTimeout
+	}
+}
@@ -0,0 +1,97 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestShutdownOrder(t *testing.T) {
+	m := NewManager(testLogger)
+
+	var order []string
+	for _, name := range []string{"http", "publisher", "connection"} {
+		name := name
+		m.Register(name, time.Second, func(ctx context.Context) error {
+			order = append(order, name)
+			return nil
+		})
+	}
+
+	if err := m.Shutdown(); err != nil {
+		t.Fatalf("Shutdown error: %v", err)
+	}
+	if got := strings.Join(order, ","); got != "http,publisher,connection" {
+		t.Errorf("stop order = %s; want http,publisher,connection", got)
+	}
+}
+
+func TestShutdownTimeoutContinues(t *testing.T) {
+	m := NewManager(testLogger)
+
+	var stoppedAfter bool
+	m.Register("stuck", 20*time.Millisecond, func(ctx context.Context) error {
+		time.Sleep(200 * time.Millisecond) // ignores ctx
+		return nil
+	})
+	m.Register("next", time.Second, func(ctx context.Context) error {
+		stoppedAfter = true
+		return nil
+	})
+
+	start := time.Now()
+	err := m.Shutdown()
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Shutdown took %v; want it bounded by the component timeout", elapsed)
+	}
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("Shutdown error = %v; want ErrTimeout", err)
+	}
+	if !strings.Contains(err.Error(), "stuck") {
+		t.Errorf("Shutdown error = %v; want it to name the component", err)
+	}
+	if !stoppedAfter {
+		t.Error("components after a timed-out one should still be stopped")
+	}
+}
+
+func TestShutdownContextDeadline(t *testing.T) {
+	m := NewManager(testLogger)
+
+	var deadline time.Duration
+	m.Register("http", 50*time.Millisecond, func(ctx context.Context) error {
+		d, ok := ctx.Deadline()
+		if !ok {
+			t.Error("stop context should have a deadline")
+		}
+		deadline = time.Until(d)
+		return nil
+	})
+
+	if err := m.Shutdown(); err != nil {
+		t.Fatalf("Shutdown error: %v", err)
+	}
+	if deadline <= 0 || deadline > 50*time.Millisecond {
+		t.Errorf("deadline = %v; want within the 50ms component timeout", deadline)
+	}
+}
+
+func TestShutdownJoinsErrors(t *testing.T) {
+	m := NewManager(testLogger)
+	errA := errors.New("a failed")
+	errB := errors.New("b failed")
+
+	m.Register("a", time.Second, func(ctx context.Context) error { return errA })
+	m.Register("b", time.Second, func(ctx context.Context) error { return errB })
+
+	err := m.Shutdown()
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("Shutdown error = %v; want both component errors", err)
+	}
+}
@@ -0,0 +1,44 @@
+// Package logging configures structured loggers for the GridFlow-Dynamics platform.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Supported output formats.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// New creates a logger writing to w in the given format. The level is read
+// from level on every call, so it can be changed at runtime.
+func New(w io.Writer, level *slog.LevelVar, format string) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{Level: level}
+
+	switch strings.ToLower(format) {
+	case FormatText, "":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("formato de log desconocido: %q", format)
+	}
+}
+
+// ParseLevel parses debug, info, warn or error, case-insensitively.
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return level, fmt.Errorf("nivel de log desconocido: %q", s)
+	}
+	return level, nil
+}
+
+// Component returns a child logger tagged with the component name.
+func Component(logger *slog.Logger, name string) *slog.Logger {
+	return logger.With("component", name)
+}
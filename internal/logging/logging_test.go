@@ -0,0 +1,96 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected slog.Level
+		wantErr  bool
+	}{
+		{input: "debug", expected: slog.LevelDebug},
+		{input: "INFO", expected: slog.LevelInfo},
+		{input: "warn", expected: slog.LevelWarn},
+		{input: "error", expected: slog.LevelError},
+		{input: "verbose", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			level, err := ParseLevel(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseLevel(%q) error = nil; want error", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseLevel(%q) error: %v", tt.input, err)
+			}
+			if level != tt.expected {
+				t.Errorf("ParseLevel(%q) = %v; want %v", tt.input, level, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewJSONComponentFields(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+
+	logger, err := New(&buf, level, FormatJSON)
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	Component(logger, "handler").Warn("rate limit excedido", "grupo_trabajo", "G0/TEST")
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("log output is not JSON: %v (%s)", err, buf.String())
+	}
+	if entry["level"] != "WARN" {
+		t.Errorf("level = %v; want WARN", entry["level"])
+	}
+	if entry["component"] != "handler" {
+		t.Errorf("component = %v; want handler", entry["component"])
+	}
+	if entry["grupo_trabajo"] != "G0/TEST" {
+		t.Errorf("grupo_trabajo = %v; want G0/TEST", entry["grupo_trabajo"])
+	}
+}
+
+func TestNewLevelFiltering(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+
+	logger, err := New(&buf, level, FormatText)
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	logger.Debug("mensaje por mensaje")
+	if buf.Len() != 0 {
+		t.Errorf("Debug should be filtered at info level, got %q", buf.String())
+	}
+
+	// Level changes apply to existing loggers
+	level.Set(slog.LevelDebug)
+	logger.Debug("mensaje por mensaje")
+	if !strings.Contains(buf.String(), "level=DEBUG") {
+		t.Errorf("Debug should be written after lowering level, got %q", buf.String())
+	}
+}
+
+func TestNewUnknownFormat(t *testing.T) {
+	if _, err := New(&bytes.Buffer{}, new(slog.LevelVar), "xml"); err == nil {
+		t.Error("New with unknown format should fail")
+	}
+}
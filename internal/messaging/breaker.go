@@ -0,0 +1,188 @@
+package messaging
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ErrCircuitOpen indica que el circuito del subject está abierto y la
+// publicación se rechazó sin intentarse. Allow lo retorna como
+// *CircuitOpenError, que indica cuándo conviene reintentar.
+var ErrCircuitOpen = errors.New("circuito de publicación abierto")
+
+// CircuitOpenError es el rechazo de un circuito abierto. RetryAfter es lo que
+// falta de la espera del circuito, o cero si hay una publicación de prueba
+// en curso.
+type CircuitOpenError struct {
+	Subject    string
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%v para %s", ErrCircuitOpen, e.Subject)
+}
+
+// Is hace que errors.Is(err, ErrCircuitOpen) reconozca el rechazo.
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// BreakerState es el estado del circuito de un subject.
+type BreakerState int
+
+const (
+	// BreakerClosed deja pasar todas las publicaciones.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rechaza las publicaciones hasta que termine la espera.
+	BreakerOpen
+	// BreakerHalfOpen deja pasar una única publicación de prueba.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// Breaker es un circuit breaker por subject para las publicaciones. Tras
+// threshold fallos consecutivos abre el circuito y las publicaciones fallan de
+// inmediato con ErrCircuitOpen durante cooldown; luego deja pasar una
+// publicación de prueba que lo cierra si tiene éxito o lo vuelve a abrir si falla.
+//
+// Breaker implementa prometheus.Collector con el estado y las aperturas de
+// cada circuito.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+
+	state *prometheus.GaugeVec
+	trips *prometheus.CounterVec
+}
+
+type circuit struct {
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewBreaker crea un circuit breaker que abre tras threshold fallos
+// consecutivos y prueba de nuevo tras cooldown.
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+		circuits:  make(map[string]*circuit),
+		state: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "gridflow_publish_circuit_state",
+			Help: "Publish circuit breaker state per subject: 0 closed, 1 open, 2 half-open.",
+		}, []string{"subject"}),
+		trips: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gridflow_publish_circuit_trips_total",
+			Help: "Times the publish circuit breaker opened per subject.",
+		}, []string{"subject"}),
+	}
+}
+
+// Allow retorna *CircuitOpenError si la publicación en subject debe rechazarse.
+// Tras la espera del circuito abierto autoriza una sola publicación de prueba.
+func (b *Breaker) Allow(subject string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(subject)
+	switch c.state {
+	case BreakerOpen:
+		if elapsed := b.now().Sub(c.openedAt); elapsed < b.cooldown {
+			return &CircuitOpenError{Subject: subject, RetryAfter: b.cooldown - elapsed}
+		}
+		b.setState(subject, c, BreakerHalfOpen)
+		c.probing = true
+		return nil
+	case BreakerHalfOpen:
+		if c.probing {
+			return &CircuitOpenError{Subject: subject}
+		}
+		c.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Record registra el resultado de una publicación autorizada por Allow. Los
+// resultados que llegan con el circuito abierto son de publicaciones en curso
+// al abrirse y se ignoran, para no extender su espera.
+func (b *Breaker) Record(subject string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(subject)
+	if c.state == BreakerOpen {
+		return
+	}
+	c.probing = false
+	if err == nil {
+		c.failures = 0
+		b.setState(subject, c, BreakerClosed)
+		return
+	}
+
+	c.failures++
+	if c.state == BreakerHalfOpen || c.failures >= b.threshold {
+		c.openedAt = b.now()
+		b.trips.WithLabelValues(subject).Inc()
+		b.setState(subject, c, BreakerOpen)
+	}
+}
+
+// State retorna el estado actual del circuito de subject.
+func (b *Breaker) State(subject string) BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.circuit(subject).state
+}
+
+// Describe implementa prometheus.Collector.
+func (b *Breaker) Describe(ch chan<- *prometheus.Desc) {
+	b.state.Describe(ch)
+	b.trips.Describe(ch)
+}
+
+// Collect implementa prometheus.Collector.
+func (b *Breaker) Collect(ch chan<- prometheus.Metric) {
+	b.state.Collect(ch)
+	b.trips.Collect(ch)
+}
+
+// circuit retorna el circuito de subject, creándolo cerrado. Requiere b.mu.
+func (b *Breaker) circuit(subject string) *circuit {
+	c, ok := b.circuits[subject]
+	if !ok {
+		c = &circuit{}
+		b.circuits[subject] = c
+		b.state.WithLabelValues(subject).Set(float64(BreakerClosed))
+	}
+	return c
+}
+
+// setState cambia el estado de c y actualiza la métrica. Requiere b.mu.
+func (b *Breaker) setState(subject string, c *circuit, state BreakerState) {
+	c.state = state
+	b.state.WithLabelValues(subject).Set(float64(state))
+}
@@ -0,0 +1,147 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestBreakerCicloDeEstados(t *testing.T) {
+	ahora := time.Date(2024, 1, 15, 13, 0, 0, 0, time.UTC)
+	breaker := NewBreaker(3, 10*time.Second)
+	breaker.now = func() time.Time { return ahora }
+
+	var fallar bool
+	intentos := 0
+	publisher := publisherFalso(func(*nats.Msg) error {
+		intentos++
+		if fallar {
+			return nats.ErrMaxPayload
+		}
+		return nil
+	}, WithBreaker(breaker))
+	publicar := func() error {
+		return publisher.Publish(context.Background(), "test.subject", "x")
+	}
+
+	// closed → open tras tres fallos consecutivos
+	fallar = true
+	for i := 0; i < 3; i++ {
+		if err := publicar(); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("publicación %d: error = %v; esperado el fallo del envío", i+1, err)
+		}
+	}
+	if breaker.State("test.subject") != BreakerOpen {
+		t.Fatalf("estado = %s; esperado open", breaker.State("test.subject"))
+	}
+
+	// open: falla de inmediato sin intentar el envío e indica la espera restante
+	ahora = ahora.Add(4 * time.Second)
+	err := publicar()
+	if !errors.Is(err, ErrCircuitOpen) || intentos != 3 {
+		t.Fatalf("error = %v, intentos = %d; esperado ErrCircuitOpen sin envío", err, intentos)
+	}
+	var circuitErr *CircuitOpenError
+	if !errors.As(err, &circuitErr) || circuitErr.RetryAfter != 6*time.Second {
+		t.Errorf("error = %v; esperado CircuitOpenError con 6s de espera restante", err)
+	}
+
+	// half-open: tras la espera una prueba fallida vuelve a abrir
+	ahora = ahora.Add(6 * time.Second)
+	if err := publicar(); err == nil || errors.Is(err, ErrCircuitOpen) || intentos != 4 {
+		t.Fatalf("error = %v, intentos = %d; esperado una prueba fallida", err, intentos)
+	}
+	if breaker.State("test.subject") != BreakerOpen {
+		t.Fatalf("estado = %s; esperado open tras la prueba fallida", breaker.State("test.subject"))
+	}
+
+	// half-open → closed con una prueba exitosa
+	ahora = ahora.Add(10 * time.Second)
+	fallar = false
+	if err := publicar(); err != nil {
+		t.Fatalf("prueba error = %v; esperado éxito", err)
+	}
+	if breaker.State("test.subject") != BreakerClosed {
+		t.Errorf("estado = %s; esperado closed", breaker.State("test.subject"))
+	}
+
+	esperado := `
+# HELP gridflow_publish_circuit_trips_total Times the publish circuit breaker opened per subject.
+# TYPE gridflow_publish_circuit_trips_total counter
+gridflow_publish_circuit_trips_total{subject="test.subject"} 2
+`
+	if err := testutil.CollectAndCompare(breaker, strings.NewReader(esperado), "gridflow_publish_circuit_trips_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBreakerUnaPruebaALaVez(t *testing.T) {
+	ahora := time.Date(2024, 1, 15, 13, 0, 0, 0, time.UTC)
+	breaker := NewBreaker(1, time.Second)
+	breaker.now = func() time.Time { return ahora }
+
+	breaker.Record("a", errors.New("fallo"))
+	ahora = ahora.Add(time.Second)
+
+	if err := breaker.Allow("a"); err != nil {
+		t.Fatalf("Allow = %v; esperado la prueba autorizada", err)
+	}
+	if err := breaker.Allow("a"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Allow = %v; esperado ErrCircuitOpen con una prueba en curso", err)
+	}
+	if err := breaker.Allow("b"); err != nil {
+		t.Errorf("Allow = %v; cada subject tiene su propio circuito", err)
+	}
+	if got := testutil.ToFloat64(breaker.state.WithLabelValues("a")); got != float64(BreakerHalfOpen) {
+		t.Errorf("gridflow_publish_circuit_state = %v; esperado %d", got, BreakerHalfOpen)
+	}
+}
+
+func TestBreakerIgnoraResultadosConCircuitoAbierto(t *testing.T) {
+	ahora := time.Date(2024, 1, 15, 13, 0, 0, 0, time.UTC)
+	breaker := NewBreaker(2, 10*time.Second)
+	breaker.now = func() time.Time { return ahora }
+
+	// Tres publicaciones autorizadas en curso; las dos primeras abren el circuito
+	for i := 0; i < 3; i++ {
+		if err := breaker.Allow("a"); err != nil {
+			t.Fatalf("Allow = %v; esperado nil con el circuito cerrado", err)
+		}
+	}
+	breaker.Record("a", errors.New("fallo"))
+	breaker.Record("a", errors.New("fallo"))
+
+	// El fallo tardío de la tercera no reinicia la espera ni cuenta otra apertura
+	ahora = ahora.Add(4 * time.Second)
+	breaker.Record("a", errors.New("fallo"))
+	var circuitErr *CircuitOpenError
+	if err := breaker.Allow("a"); !errors.As(err, &circuitErr) || circuitErr.RetryAfter != 6*time.Second {
+		t.Errorf("Allow = %v; esperado CircuitOpenError con 6s de espera restante", err)
+	}
+
+	// Tampoco un éxito tardío cierra el circuito antes de tiempo
+	breaker.Record("a", nil)
+	if got := breaker.State("a"); got != BreakerOpen {
+		t.Errorf("estado = %s; esperado open", got)
+	}
+	if got := testutil.ToFloat64(breaker.trips.WithLabelValues("a")); got != 1 {
+		t.Errorf("gridflow_publish_circuit_trips_total = %v; esperado 1", got)
+	}
+}
+
+func TestBreakerIgnoraErroresDeSerializacion(t *testing.T) {
+	breaker := NewBreaker(1, time.Minute)
+	publisher := publisherFalso(func(*nats.Msg) error { return nil }, WithBreaker(breaker))
+
+	if err := publisher.Publish(context.Background(), "test.subject", func() {}); !errors.Is(err, ErrMarshal) {
+		t.Fatalf("Publish error = %v; esperado ErrMarshal", err)
+	}
+	if breaker.State("test.subject") != BreakerClosed {
+		t.Errorf("estado = %s; un payload inválido no debe abrir el circuito", breaker.State("test.subject"))
+	}
+}
@@ -0,0 +1,128 @@
+package messaging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nuid"
+)
+
+// Tipos CloudEvents de los eventos publicados. Son estables: no dependen del
+// prefijo de subject configurado, de modo que los consumidores del event mesh
+// pueden filtrar por ellos en todos los entornos.
+const (
+	TypeInventarioCuadrilla = "com.gridflow.inventario.cuadrilla.v1"
+)
+
+const (
+	// SourceAPI es el atributo source de los eventos publicados por la API.
+	SourceAPI = "/gridflow-dynamics/api"
+
+	// ContentTypeHeader es el header NATS que indica el formato del payload.
+	ContentTypeHeader = "Content-Type"
+
+	// ContentTypeCloudEvents identifica un sobre CloudEvents en modo estructurado.
+	ContentTypeCloudEvents = "application/cloudevents+json"
+
+	// ContentTypeJSON identifica un payload JSON sin sobre.
+	ContentTypeJSON = "application/json"
+
+	cloudEventsSpecVersion = "1.0"
+)
+
+// CloudEvent es el sobre CloudEvents 1.0 en modo estructurado JSON.
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// cloudEvents configura el sobre que el publisher aplica a cada payload.
+type cloudEvents struct {
+	source string
+	types  map[string]string
+}
+
+// eventType retorna el tipo registrado para subject, o uno derivado del
+// subject si no hay ninguno.
+func (c *cloudEvents) eventType(subject string) string {
+	if t, ok := c.types[subject]; ok {
+		return t
+	}
+	return "com.gridflow." + subject
+}
+
+func (c *cloudEvents) wrap(subject string, payload []byte) ([]byte, error) {
+	return json.Marshal(CloudEvent{
+		SpecVersion:     cloudEventsSpecVersion,
+		ID:              nuid.Next(),
+		Source:          c.source,
+		Type:            c.eventType(subject),
+		Subject:         subject,
+		Time:            time.Now().UTC(),
+		DataContentType: ContentTypeJSON,
+		Data:            payload,
+	})
+}
+
+// PublisherOption configura un Publisher.
+type PublisherOption func(*Publisher)
+
+// WithCloudEvents envuelve cada payload publicado en un sobre CloudEvents con
+// el source indicado. types asocia cada subject a su tipo; los subjects sin
+// tipo registrado usan "com.gridflow.<subject>".
+func WithCloudEvents(source string, types map[string]string) PublisherOption {
+	return func(p *Publisher) {
+		p.cloudEvents = &cloudEvents{source: source, types: types}
+	}
+}
+
+// Decode deserializa el payload de msg en v. Acepta tanto sobres CloudEvents
+// como JSON sin sobre, de modo que los consumidores funcionan mientras los
+// publicadores cambian de modo. Retorna el sobre, o nil si el mensaje no lo traía.
+func Decode(msg *nats.Msg, v any) (*CloudEvent, error) {
+	if !isCloudEvent(msg) {
+		if err := json.Unmarshal(msg.Data, v); err != nil {
+			return nil, fmt.Errorf("fallo al deserializar mensaje: %w", err)
+		}
+		return nil, nil
+	}
+
+	var event CloudEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		return nil, fmt.Errorf("fallo al deserializar sobre CloudEvents: %w", err)
+	}
+	if event.SpecVersion != cloudEventsSpecVersion {
+		return nil, fmt.Errorf("versión CloudEvents no soportada: %q", event.SpecVersion)
+	}
+	if err := json.Unmarshal(event.Data, v); err != nil {
+		return nil, fmt.Errorf("fallo al deserializar data del evento %s: %w", event.ID, err)
+	}
+	return &event, nil
+}
+
+// isCloudEvent detecta un sobre por el header Content-Type o, si falta, por
+// la presencia del atributo specversion en el JSON.
+func isCloudEvent(msg *nats.Msg) bool {
+	if contentType := msg.Header.Get(ContentTypeHeader); contentType != "" {
+		return strings.HasPrefix(contentType, ContentTypeCloudEvents)
+	}
+
+	data := bytes.TrimSpace(msg.Data)
+	if len(data) == 0 || data[0] != '{' {
+		return false
+	}
+	var probe struct {
+		SpecVersion *string `json:"specversion"`
+	}
+	return json.Unmarshal(data, &probe) == nil && probe.SpecVersion != nil
+}
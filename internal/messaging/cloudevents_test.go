@@ -0,0 +1,139 @@
+package messaging
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+type eventoPrueba struct {
+	Estado string `json:"estado"`
+}
+
+func TestPublisherCloudEvents(t *testing.T) {
+	srv := iniciarServidorNATS(t)
+	conn, _ := conectar(t, srv)
+
+	publisher, err := NewPublisher(conn, WithCloudEvents(SourceAPI, map[string]string{"test.ce": TypeInventarioCuadrilla}))
+	if err != nil {
+		t.Fatalf("Error al crear publisher: %v", err)
+	}
+	sub, err := conn.GetConn().SubscribeSync("test.>")
+	if err != nil {
+		t.Fatalf("Error al suscribir: %v", err)
+	}
+
+	antes := time.Now().Add(-time.Second)
+	if err := publisher.Publish(context.Background(), "test.ce", eventoPrueba{Estado: "trabajando"}); err != nil {
+		t.Fatalf("Error al publicar: %v", err)
+	}
+	if err := publisher.Publish(context.Background(), "test.otro", eventoPrueba{Estado: "en_ruta"}); err != nil {
+		t.Fatalf("Error al publicar: %v", err)
+	}
+
+	msg, err := sub.NextMsg(2 * time.Second)
+	if err != nil {
+		t.Fatalf("No se recibió el mensaje: %v", err)
+	}
+	if got := msg.Header.Get(ContentTypeHeader); got != ContentTypeCloudEvents {
+		t.Errorf("Content-Type = %q; esperado %q", got, ContentTypeCloudEvents)
+	}
+
+	var evento eventoPrueba
+	ce, err := Decode(msg, &evento)
+	if err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+	if ce == nil {
+		t.Fatal("Decode no retornó el sobre CloudEvents")
+	}
+	if evento.Estado != "trabajando" {
+		t.Errorf("Estado = %q; esperado trabajando", evento.Estado)
+	}
+	if ce.SpecVersion != "1.0" || ce.ID == "" || ce.Source != SourceAPI || ce.Type != TypeInventarioCuadrilla ||
+		ce.Subject != "test.ce" || ce.DataContentType != ContentTypeJSON || ce.Time.Before(antes) {
+		t.Errorf("Sobre = %+v; atributos inesperados", ce)
+	}
+
+	msg, err = sub.NextMsg(2 * time.Second)
+	if err != nil {
+		t.Fatalf("No se recibió el mensaje: %v", err)
+	}
+	ce, err = Decode(msg, &evento)
+	if err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+	if ce.Type != "com.gridflow.test.otro" {
+		t.Errorf("Type = %q; esperado com.gridflow.test.otro para un subject sin tipo registrado", ce.Type)
+	}
+}
+
+func TestDecodeModoMixto(t *testing.T) {
+	srv := iniciarServidorNATS(t)
+	conn, bare := conectar(t, srv)
+
+	sub, err := conn.GetConn().SubscribeSync("test.mixto")
+	if err != nil {
+		t.Fatalf("Error al suscribir: %v", err)
+	}
+
+	if err := bare.Publish(context.Background(), "test.mixto", eventoPrueba{Estado: "en_pausa"}); err != nil {
+		t.Fatalf("Error al publicar: %v", err)
+	}
+	msg, err := sub.NextMsg(2 * time.Second)
+	if err != nil {
+		t.Fatalf("No se recibió el mensaje: %v", err)
+	}
+	if got := msg.Header.Get(ContentTypeHeader); got != ContentTypeJSON {
+		t.Errorf("Content-Type = %q; esperado %q", got, ContentTypeJSON)
+	}
+
+	var evento eventoPrueba
+	ce, err := Decode(msg, &evento)
+	if err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+	if ce != nil || evento.Estado != "en_pausa" {
+		t.Errorf("Decode = (%+v, %+v); esperado JSON sin sobre", ce, evento)
+	}
+}
+
+func TestDecodeSinHeader(t *testing.T) {
+	tests := []struct {
+		nombre    string
+		data      string
+		sobre     bool
+		esperaErr bool
+	}{
+		{nombre: "JSON sin sobre", data: `{"estado":"finalizado"}`},
+		{nombre: "sobre detectado por specversion", data: `{"specversion":"1.0","id":"1","source":"/x","type":"t","datacontenttype":"application/json","data":{"estado":"finalizado"}}`, sobre: true},
+		{nombre: "versión no soportada", data: `{"specversion":"0.3","id":"1","data":{}}`, esperaErr: true},
+		{nombre: "JSON inválido", data: `no es json`, esperaErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			msg := &nats.Msg{Subject: "test", Data: []byte(tt.data)}
+
+			var evento eventoPrueba
+			ce, err := Decode(msg, &evento)
+			if tt.esperaErr {
+				if err == nil {
+					t.Error("Decode error = nil; esperado error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decode error: %v", err)
+			}
+			if (ce != nil) != tt.sobre {
+				t.Errorf("sobre = %v; esperado %v", ce != nil, tt.sobre)
+			}
+			if evento.Estado != "finalizado" {
+				t.Errorf("Estado = %q; esperado finalizado", evento.Estado)
+			}
+		})
+	}
+}
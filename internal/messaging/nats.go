@@ -4,11 +4,21 @@ package messaging
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/120m4n/GridFlow-Dynamics/internal/tracing"
 )
 
 // Subjects para la arquitectura orientada a eventos.
@@ -16,20 +26,44 @@ const (
 	SubjectInventarioCuadrilla = "inventario.cuadrilla"
 )
 
+// Errores de publicación. Publish los envuelve, de modo que los llamadores
+// distinguen con errors.Is los fallos transitorios de los permanentes.
+var (
+	// ErrNotConnected indica que no hay una conexión activa con NATS.
+	ErrNotConnected = errors.New("conexión NATS no está activa")
+
+	// ErrMarshal indica que el payload no pudo serializarse; reintentar no sirve.
+	ErrMarshal = errors.New("payload no serializable")
+
+	// ErrPublishTimeout indica que el contexto expiró mientras se reintentaba
+	// un error transitorio.
+	ErrPublishTimeout = errors.New("tiempo de publicación agotado")
+)
+
+// Dialer abre una conexión nativa de NATS; nats.Connect es la implementación por defecto.
+type Dialer func(url string, options ...nats.Option) (*nats.Conn, error)
+
 // Connection representa una conexión a NATS con soporte de reconexión.
 type Connection struct {
-	url  string
+	url    string
+	dial   Dialer
+	logger *slog.Logger
+
+	mu   sync.RWMutex
 	conn *nats.Conn
 }
 
 // NewConnection crea una nueva conexión NATS.
-func NewConnection(url string) *Connection {
+func NewConnection(url string, logger *slog.Logger) *Connection {
 	return &Connection{
-		url: url,
+		url:    url,
+		dial:   nats.Connect,
+		logger: logger,
 	}
 }
 
-// Connect establece la conexión con NATS.
+// Connect establece la conexión con NATS. Puede llamarse de nuevo tras un
+// intento fallido o tras Close; una conexión previa se reemplaza y se cierra.
 func (c *Connection) Connect() error {
 	opts := []nats.Option{
 		nats.Name("GridFlow-Dynamics"),
@@ -37,71 +71,229 @@ func (c *Connection) Connect() error {
 		nats.ReconnectWait(2 * time.Second),
 		nats.DisconnectErrHandler(func(nc *nats.Conn, err error) {
 			if err != nil {
-				log.Printf("NATS desconectado: %v", err)
+				c.logger.Warn("NATS desconectado", "error", err)
 			}
 		}),
 		nats.ReconnectHandler(func(nc *nats.Conn) {
-			log.Printf("NATS reconectado a %s", nc.ConnectedUrl())
+			c.logger.Info("NATS reconectado", "url", nc.ConnectedUrl())
 		}),
 	}
 
-	conn, err := nats.Connect(c.url, opts...)
+	conn, err := c.dial(c.url, opts...)
 	if err != nil {
 		return fmt.Errorf("fallo al conectar a NATS: %w", err)
 	}
 
+	c.mu.Lock()
+	prev := c.conn
 	c.conn = conn
-	log.Printf("Conectado a NATS en %s", c.url)
+	c.mu.Unlock()
+	if prev != nil {
+		prev.Close()
+	}
+	c.logger.Info("Conectado a NATS", "url", c.url)
 	return nil
 }
 
-// Close cierra la conexión NATS.
+// Close cierra la conexión NATS. Es seguro llamarlo sin haber conectado,
+// sobre una Connection nil o más de una vez.
 func (c *Connection) Close() error {
-	if c.conn != nil {
-		c.conn.Close()
-		log.Println("Conexión NATS cerrada")
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	conn := c.conn
+	c.conn = nil
+	c.mu.Unlock()
+
+	if conn != nil {
+		conn.Close()
+		c.logger.Info("Conexión NATS cerrada")
 	}
 	return nil
 }
 
 // IsConnected retorna si la conexión está activa.
 func (c *Connection) IsConnected() bool {
-	return c.conn != nil && c.conn.IsConnected()
+	conn := c.GetConn()
+	return conn != nil && conn.IsConnected()
 }
 
 // GetConn retorna la conexión nativa de NATS.
 func (c *Connection) GetConn() *nats.Conn {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.conn
 }
 
 // Publisher publica eventos a NATS.
 type Publisher struct {
-	conn *Connection
+	conn        *Connection
+	logger      *slog.Logger
+	cloudEvents *cloudEvents
+
+	// send entrega el mensaje ya armado; los tests lo reemplazan.
+	send func(*nats.Msg) error
+
+	retries      int
+	retryBackoff time.Duration
+	breaker      *Breaker
+	stats        *PublishStats
 }
 
 // NewPublisher crea un nuevo publisher.
-func NewPublisher(conn *Connection) (*Publisher, error) {
+func NewPublisher(conn *Connection, opts ...PublisherOption) (*Publisher, error) {
 	if !conn.IsConnected() {
-		return nil, fmt.Errorf("conexión NATS no está activa")
+		return nil, ErrNotConnected
+	}
+	p := &Publisher{conn: conn, logger: conn.logger}
+	p.send = p.sendConn
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p, nil
+}
+
+// WithRetry reintenta hasta attempts veces los errores transitorios de
+// publicación (sin conexión o buffer de reconexión lleno), esperando backoff
+// con jitter y duplicándolo en cada intento. Los reintentos terminan cuando
+// expira el contexto de Publish, con ErrPublishTimeout.
+func WithRetry(attempts int, backoff time.Duration) PublisherOption {
+	return func(p *Publisher) {
+		p.retries = attempts
+		p.retryBackoff = backoff
 	}
-	return &Publisher{conn: conn}, nil
 }
 
-// Publish publica un mensaje a un subject específico.
+// Publish publica un mensaje a un subject específico. El contexto de traza de
+// ctx viaja en los headers del mensaje NATS. Con WithCloudEvents el payload
+// se envuelve en un sobre CloudEvents.
 func (p *Publisher) Publish(ctx context.Context, subject string, data interface{}) error {
+	ctx, span := otel.Tracer(tracing.InstrumentationName).Start(ctx, "publish "+subject,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String("nats"),
+			semconv.MessagingDestinationName(subject),
+		),
+	)
+	defer span.End()
+
 	payload, err := json.Marshal(data)
 	if err != nil {
-		return fmt.Errorf("fallo al serializar mensaje: %w", err)
+		span.SetStatus(codes.Error, err.Error())
+		return fmt.Errorf("fallo al serializar mensaje: %w: %w", ErrMarshal, err)
 	}
 
-	if err := p.conn.conn.Publish(subject, payload); err != nil {
-		return fmt.Errorf("fallo al publicar mensaje: %w", err)
+	contentType := ContentTypeJSON
+	if p.cloudEvents != nil {
+		if payload, err = p.cloudEvents.wrap(subject, payload); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return fmt.Errorf("fallo al serializar sobre CloudEvents: %w: %w", ErrMarshal, err)
+		}
+		contentType = ContentTypeCloudEvents
+	}
+
+	msg := nats.NewMsg(subject)
+	msg.Data = payload
+	msg.Header.Set(ContentTypeHeader, contentType)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(msg.Header))
+
+	if p.breaker != nil {
+		if err := p.breaker.Allow(subject); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return fmt.Errorf("fallo al publicar mensaje: %w", err)
+		}
+	}
+	err = p.sendWithRetry(ctx, msg)
+	if p.breaker != nil {
+		p.breaker.Record(subject, err)
+	}
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	if p.stats != nil {
+		p.stats.Record(subject)
 	}
 
-	log.Printf("Evento publicado a subject '%s'", subject)
+	p.logger.Debug("Evento publicado", "subject", subject)
 	return nil
 }
 
+// WithBreaker rechaza de inmediato con ErrCircuitOpen las publicaciones a un
+// subject cuyo circuito está abierto en b. Los errores de serialización no
+// cuentan como fallos.
+func WithBreaker(b *Breaker) PublisherOption {
+	return func(p *Publisher) {
+		p.breaker = b
+	}
+}
+
+// WithStats registra en s cada publicación exitosa.
+func WithStats(s *PublishStats) PublisherOption {
+	return func(p *Publisher) {
+		p.stats = s
+	}
+}
+
+// sendWithRetry entrega msg reintentando los errores transitorios según WithRetry.
+func (p *Publisher) sendWithRetry(ctx context.Context, msg *nats.Msg) error {
+	backoff := p.retryBackoff
+	for attempt := 1; ; attempt++ {
+		err := p.send(msg)
+		if err == nil {
+			return nil
+		}
+		if !isTransient(err) || attempt > p.retries {
+			return fmt.Errorf("fallo al publicar mensaje: %w", err)
+		}
+
+		wait := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+		p.logger.Debug("Reintentando publicación", "subject", msg.Subject, "intento", attempt, "espera", wait, "error", err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w tras %d intentos: %w", ErrPublishTimeout, attempt, err)
+		case <-time.After(wait):
+		}
+		backoff *= 2
+	}
+}
+
+// sendConn publica msg en la conexión vigente, traduciendo una conexión
+// cerrada o ausente a ErrNotConnected.
+func (p *Publisher) sendConn(msg *nats.Msg) error {
+	conn := p.conn.GetConn()
+	if conn == nil {
+		return ErrNotConnected
+	}
+	err := conn.PublishMsg(msg)
+	if errors.Is(err, nats.ErrConnectionClosed) || errors.Is(err, nats.ErrConnectionDraining) {
+		return fmt.Errorf("%w: %w", ErrNotConnected, err)
+	}
+	return err
+}
+
+// isTransient indica si un error de publicación puede resolverse reintentando.
+func isTransient(err error) bool {
+	return errors.Is(err, ErrNotConnected) || errors.Is(err, nats.ErrReconnectBufExceeded)
+}
+
+// Flush espera a que el servidor NATS confirme los mensajes publicados pendientes.
+func (p *Publisher) Flush(ctx context.Context) error {
+	conn := p.conn.GetConn()
+	if conn == nil || !conn.IsConnected() {
+		return ErrNotConnected
+	}
+	return conn.FlushWithContext(ctx)
+}
+
+// ExtractContext retorna ctx con el contexto de traza propagado en los headers
+// de msg, para que los consumidores continúen la traza del publicador.
+func ExtractContext(ctx context.Context, msg *nats.Msg) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(msg.Header))
+}
+
 // Close cierra el publisher.
 func (p *Publisher) Close() error {
 	return nil
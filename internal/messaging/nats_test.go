@@ -0,0 +1,283 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	natsserver "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// iniciarServidorNATS levanta un servidor NATS embebido en un puerto aleatorio.
+func iniciarServidorNATS(t *testing.T) *server.Server {
+	t.Helper()
+	opts := natsserver.DefaultTestOptions
+	opts.Port = server.RANDOM_PORT
+	srv := natsserver.RunServer(&opts)
+	t.Cleanup(srv.Shutdown)
+	return srv
+}
+
+// conectar crea una conexión y un publisher contra el servidor embebido.
+func conectar(t *testing.T, srv *server.Server) (*Connection, *Publisher) {
+	t.Helper()
+	conn := NewConnection(srv.ClientURL(), testLogger)
+	if err := conn.Connect(); err != nil {
+		t.Fatalf("Error al conectar: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	publisher, err := NewPublisher(conn)
+	if err != nil {
+		t.Fatalf("Error al crear publisher: %v", err)
+	}
+	return conn, publisher
+}
+
+// instalarTrazas registra un tracer provider en memoria y lo restaura al terminar.
+func instalarTrazas(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	prevProvider := otel.GetTracerProvider()
+	prevPropagator := otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+	return recorder
+}
+
+func TestConnectionCloseSinConectar(t *testing.T) {
+	conn := NewConnection("nats://127.0.0.1:1", testLogger)
+	if err := conn.Close(); err != nil {
+		t.Errorf("Close error = %v; esperado nil", err)
+	}
+	if err := conn.Close(); err != nil {
+		t.Errorf("Segundo Close error = %v; esperado nil", err)
+	}
+
+	var nilConn *Connection
+	if err := nilConn.Close(); err != nil {
+		t.Errorf("Close sobre nil error = %v; esperado nil", err)
+	}
+}
+
+func TestConnectionDobleClose(t *testing.T) {
+	srv := iniciarServidorNATS(t)
+	conn := NewConnection(srv.ClientURL(), testLogger)
+	if err := conn.Connect(); err != nil {
+		t.Fatalf("Error al conectar: %v", err)
+	}
+	nc := conn.GetConn()
+
+	conn.Close()
+	conn.Close()
+	if !nc.IsClosed() {
+		t.Error("La conexión nativa debe quedar cerrada")
+	}
+	if conn.IsConnected() || conn.GetConn() != nil {
+		t.Error("Tras Close no debe quedar conexión activa")
+	}
+}
+
+func TestConnectionReconectaTrasFallo(t *testing.T) {
+	srv := iniciarServidorNATS(t)
+	conn := NewConnection(srv.ClientURL(), testLogger)
+	t.Cleanup(func() { conn.Close() })
+
+	intentos := 0
+	conn.dial = func(url string, options ...nats.Option) (*nats.Conn, error) {
+		intentos++
+		if intentos == 1 {
+			return nil, nats.ErrNoServers
+		}
+		return nats.Connect(url, options...)
+	}
+
+	if err := conn.Connect(); err == nil {
+		t.Fatal("Connect error = nil; esperado fallo en el primer intento")
+	}
+	conn.Close()
+	if err := conn.Connect(); err != nil {
+		t.Fatalf("Connect error = %v; esperado éxito en el segundo intento", err)
+	}
+	if !conn.IsConnected() {
+		t.Error("Connection debe quedar conectada tras el segundo intento")
+	}
+}
+
+func TestPublisherPublish(t *testing.T) {
+	srv := iniciarServidorNATS(t)
+	conn, publisher := conectar(t, srv)
+
+	sub, err := conn.GetConn().SubscribeSync("test.subject")
+	if err != nil {
+		t.Fatalf("Error al suscribir: %v", err)
+	}
+
+	if err := publisher.Publish(context.Background(), "test.subject", map[string]string{"estado": "trabajando"}); err != nil {
+		t.Fatalf("Error al publicar: %v", err)
+	}
+
+	msg, err := sub.NextMsg(2 * time.Second)
+	if err != nil {
+		t.Fatalf("No se recibió el mensaje: %v", err)
+	}
+	if string(msg.Data) != `{"estado":"trabajando"}` {
+		t.Errorf("Data = %s; esperado {\"estado\":\"trabajando\"}", msg.Data)
+	}
+}
+
+func TestPublisherPropagaTraza(t *testing.T) {
+	recorder := instalarTrazas(t)
+	srv := iniciarServidorNATS(t)
+	conn, publisher := conectar(t, srv)
+
+	sub, err := conn.GetConn().SubscribeSync("test.trazas")
+	if err != nil {
+		t.Fatalf("Error al suscribir: %v", err)
+	}
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "padre")
+	if err := publisher.Publish(ctx, "test.trazas", map[string]int{"n": 1}); err != nil {
+		t.Fatalf("Error al publicar: %v", err)
+	}
+	parent.End()
+
+	msg, err := sub.NextMsg(2 * time.Second)
+	if err != nil {
+		t.Fatalf("No se recibió el mensaje: %v", err)
+	}
+
+	// El consumidor continúa la traza del publicador
+	consumerSpan := trace.SpanContextFromContext(ExtractContext(context.Background(), msg))
+	if consumerSpan.TraceID() != parent.SpanContext().TraceID() {
+		t.Errorf("TraceID extraído = %s; esperado %s", consumerSpan.TraceID(), parent.SpanContext().TraceID())
+	}
+
+	var publishSpan sdktrace.ReadOnlySpan
+	for _, s := range recorder.Ended() {
+		if s.Name() == "publish test.trazas" {
+			publishSpan = s
+		}
+	}
+	if publishSpan == nil {
+		t.Fatal("No se registró el span de publicación")
+	}
+	if publishSpan.SpanKind() != trace.SpanKindProducer {
+		t.Errorf("SpanKind = %v; esperado producer", publishSpan.SpanKind())
+	}
+	if publishSpan.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("El span de publicación debe ser hijo del span padre")
+	}
+	if consumerSpan.SpanID() != publishSpan.SpanContext().SpanID() {
+		t.Error("El header del mensaje debe referenciar el span de publicación")
+	}
+}
+
+// publisherFalso crea un publisher cuyo envío delega en send, sin conexión real.
+func publisherFalso(send func(*nats.Msg) error, opts ...PublisherOption) *Publisher {
+	p := &Publisher{conn: NewConnection("", testLogger), logger: testLogger, send: send}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func TestPublisherReintentaErroresTransitorios(t *testing.T) {
+	intentos := 0
+	publisher := publisherFalso(func(*nats.Msg) error {
+		intentos++
+		if intentos < 3 {
+			return nats.ErrReconnectBufExceeded
+		}
+		return nil
+	}, WithRetry(2, time.Millisecond))
+
+	if err := publisher.Publish(context.Background(), "test.subject", map[string]int{"n": 1}); err != nil {
+		t.Fatalf("Publish error = %v; esperado éxito al tercer intento", err)
+	}
+	if intentos != 3 {
+		t.Errorf("intentos = %d; esperado 3", intentos)
+	}
+}
+
+func TestPublisherErroresTipados(t *testing.T) {
+	tests := []struct {
+		nombre       string
+		data         any
+		send         error
+		retries      int
+		want         error
+		wantIntentos int
+	}{
+		{nombre: "payload no serializable", data: func() {}, want: ErrMarshal, wantIntentos: 0},
+		{nombre: "sin conexión agota reintentos", data: "x", send: ErrNotConnected, retries: 2, want: ErrNotConnected, wantIntentos: 3},
+		{nombre: "error permanente sin reintentos", data: "x", send: nats.ErrMaxPayload, retries: 2, want: nats.ErrMaxPayload, wantIntentos: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			intentos := 0
+			publisher := publisherFalso(func(*nats.Msg) error {
+				intentos++
+				return tt.send
+			}, WithRetry(tt.retries, time.Millisecond))
+
+			err := publisher.Publish(context.Background(), "test.subject", tt.data)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Publish error = %v; esperado %v", err, tt.want)
+			}
+			if intentos != tt.wantIntentos {
+				t.Errorf("intentos = %d; esperado %d", intentos, tt.wantIntentos)
+			}
+		})
+	}
+}
+
+func TestPublisherTimeoutEnReintentos(t *testing.T) {
+	publisher := publisherFalso(func(*nats.Msg) error {
+		return nats.ErrReconnectBufExceeded
+	}, WithRetry(10, 50*time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := publisher.Publish(ctx, "test.subject", "x")
+	if !errors.Is(err, ErrPublishTimeout) || !errors.Is(err, nats.ErrReconnectBufExceeded) {
+		t.Errorf("Publish error = %v; esperado ErrPublishTimeout con la causa original", err)
+	}
+}
+
+func TestPublisherConexionCerrada(t *testing.T) {
+	srv := iniciarServidorNATS(t)
+	conn, publisher := conectar(t, srv)
+	conn.Close()
+
+	if err := publisher.Publish(context.Background(), "test.subject", "x"); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Publish error = %v; esperado ErrNotConnected", err)
+	}
+}
+
+func TestExtractContextSinHeaders(t *testing.T) {
+	msg := nats.NewMsg("test.subject")
+	ctx := ExtractContext(context.Background(), msg)
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		t.Error("Un mensaje sin headers no debe producir un contexto de traza válido")
+	}
+}
@@ -0,0 +1,63 @@
+// Package natstest levanta un servidor NATS embebido para las pruebas de los
+// paquetes que publican o consumen eventos.
+package natstest
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/nats-io/nats-server/v2/server"
+	natsserver "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nats.go"
+
+	"github.com/120m4n/GridFlow-Dynamics/internal/messaging"
+)
+
+// Server levanta NATS embebido en un puerto aleatorio y lo detiene al
+// terminar la prueba.
+func Server(t testing.TB) *server.Server {
+	t.Helper()
+	opts := natsserver.DefaultTestOptions
+	opts.Port = server.RANDOM_PORT
+	srv := natsserver.RunServer(&opts)
+	t.Cleanup(srv.Shutdown)
+	return srv
+}
+
+// Publisher levanta NATS embebido y retorna un publisher conectado, creado
+// con opts.
+func Publisher(t testing.TB, opts ...messaging.PublisherOption) *messaging.Publisher {
+	t.Helper()
+	publisher, _ := connect(t, opts...)
+	return publisher
+}
+
+// Subscribed levanta NATS embebido y retorna un publisher conectado y una
+// suscripción síncrona a subject sobre la misma conexión.
+func Subscribed(t testing.TB, subject string) (*messaging.Publisher, *nats.Subscription) {
+	t.Helper()
+	publisher, conn := connect(t)
+	sub, err := conn.GetConn().SubscribeSync(subject)
+	if err != nil {
+		t.Fatalf("Error al suscribir: %v", err)
+	}
+	return publisher, sub
+}
+
+func connect(t testing.TB, opts ...messaging.PublisherOption) (*messaging.Publisher, *messaging.Connection) {
+	t.Helper()
+	srv := Server(t)
+
+	conn := messaging.NewConnection(srv.ClientURL(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := conn.Connect(); err != nil {
+		t.Fatalf("Error al conectar a NATS: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	publisher, err := messaging.NewPublisher(conn, opts...)
+	if err != nil {
+		t.Fatalf("Error al crear publisher: %v", err)
+	}
+	return publisher, conn
+}
@@ -0,0 +1,109 @@
+package messaging
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// statsWindow es la ventana del conteo móvil de publicaciones.
+const statsWindow = time.Minute
+
+// SubjectStats resume las publicaciones exitosas a un subject.
+type SubjectStats struct {
+	LastSuccess time.Time
+	LastMinute  int
+}
+
+// PublishStats registra por subject la hora de la última publicación exitosa
+// y cuántas hubo en el último minuto, para detectar subjects que dejan de
+// recibir eventos.
+//
+// PublishStats implementa prometheus.Collector.
+type PublishStats struct {
+	now func() time.Time
+
+	mu       sync.Mutex
+	subjects map[string]*subjectStats
+
+	lastSuccessDesc *prometheus.Desc
+	lastMinuteDesc  *prometheus.Desc
+}
+
+// subjectStats cuenta publicaciones en un anillo de buckets de un segundo.
+type subjectStats struct {
+	lastSuccess time.Time
+	seconds     [int(statsWindow / time.Second)]int64
+	counts      [int(statsWindow / time.Second)]int
+}
+
+// NewPublishStats crea un registro de publicaciones vacío.
+func NewPublishStats() *PublishStats {
+	return &PublishStats{
+		now:      time.Now,
+		subjects: make(map[string]*subjectStats),
+		lastSuccessDesc: prometheus.NewDesc("gridflow_publish_last_success_timestamp_seconds",
+			"Unix time of the last successful publish per subject.", []string{"subject"}, nil),
+		lastMinuteDesc: prometheus.NewDesc("gridflow_publish_last_minute",
+			"Successful publishes per subject during the last minute.", []string{"subject"}, nil),
+	}
+}
+
+// Record registra una publicación exitosa a subject.
+func (s *PublishStats) Record(subject string) {
+	now := s.now()
+	sec := now.Unix()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.subjects[subject]
+	if !ok {
+		st = &subjectStats{}
+		s.subjects[subject] = st
+	}
+	st.lastSuccess = now
+	i := sec % int64(len(st.seconds))
+	if st.seconds[i] != sec {
+		st.seconds[i] = sec
+		st.counts[i] = 0
+	}
+	st.counts[i]++
+}
+
+// Stats retorna el resumen de cada subject con al menos una publicación.
+func (s *PublishStats) Stats() map[string]SubjectStats {
+	since := s.now().Unix() - int64(statsWindow/time.Second)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := make(map[string]SubjectStats, len(s.subjects))
+	for subject, st := range s.subjects {
+		count := 0
+		for i, sec := range st.seconds {
+			if sec > since {
+				count += st.counts[i]
+			}
+		}
+		stats[subject] = SubjectStats{LastSuccess: st.lastSuccess, LastMinute: count}
+	}
+	return stats
+}
+
+// Describe implementa prometheus.Collector.
+func (s *PublishStats) Describe(ch chan<- *prometheus.Desc) {
+	ch <- s.lastSuccessDesc
+	ch <- s.lastMinuteDesc
+}
+
+// Collect implementa prometheus.Collector.
+func (s *PublishStats) Collect(ch chan<- prometheus.Metric) {
+	for subject, st := range s.Stats() {
+		ch <- prometheus.MustNewConstMetric(s.lastSuccessDesc, prometheus.GaugeValue,
+			float64(st.LastSuccess.UnixNano())/1e9, subject)
+		ch <- prometheus.MustNewConstMetric(s.lastMinuteDesc, prometheus.GaugeValue,
+			float64(st.LastMinute), subject)
+	}
+}
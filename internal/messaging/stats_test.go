@@ -0,0 +1,67 @@
+package messaging
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestPublishStats(t *testing.T) {
+	ahora := time.Date(2024, 1, 15, 13, 0, 0, 0, time.UTC)
+	stats := NewPublishStats()
+	stats.now = func() time.Time { return ahora }
+
+	var fallar bool
+	publisher := publisherFalso(func(*nats.Msg) error {
+		if fallar {
+			return nats.ErrMaxPayload
+		}
+		return nil
+	}, WithStats(stats))
+	publicar := func(subject string) {
+		publisher.Publish(context.Background(), subject, "x")
+	}
+
+	publicar("a")
+	ahora = ahora.Add(30 * time.Second)
+	publicar("a")
+	publicar("a")
+	ultima := ahora
+
+	// Los fallos no cuentan ni actualizan la última publicación
+	fallar = true
+	ahora = ahora.Add(10 * time.Second)
+	publicar("a")
+	publicar("b")
+
+	got := stats.Stats()
+	if len(got) != 1 {
+		t.Fatalf("subjects = %v; esperado solo a", got)
+	}
+	if got["a"].LastMinute != 3 || !got["a"].LastSuccess.Equal(ultima) {
+		t.Errorf("a = %+v; esperado 3 publicaciones y la última a las %v", got["a"], ultima)
+	}
+
+	// La ventana móvil descarta la publicación de hace más de un minuto
+	ahora = ahora.Add(25 * time.Second)
+	if n := stats.Stats()["a"].LastMinute; n != 2 {
+		t.Errorf("LastMinute = %d; esperado 2", n)
+	}
+	ahora = ahora.Add(time.Hour)
+	if st := stats.Stats()["a"]; st.LastMinute != 0 || !st.LastSuccess.Equal(ultima) {
+		t.Errorf("a = %+v; esperado 0 en el último minuto conservando la última publicación", st)
+	}
+
+	esperado := `
+# HELP gridflow_publish_last_success_timestamp_seconds Unix time of the last successful publish per subject.
+# TYPE gridflow_publish_last_success_timestamp_seconds gauge
+gridflow_publish_last_success_timestamp_seconds{subject="a"} 1.70532363e+09
+`
+	if err := testutil.CollectAndCompare(stats, strings.NewReader(esperado), "gridflow_publish_last_success_timestamp_seconds"); err != nil {
+		t.Error(err)
+	}
+}
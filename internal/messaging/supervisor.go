@@ -0,0 +1,134 @@
+package messaging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// PublisherProvider entrega el publisher vigente, o nil mientras no hay conexión.
+type PublisherProvider interface {
+	Publisher() *Publisher
+}
+
+// Publisher permite usar un publisher ya conectado como PublisherProvider.
+func (p *Publisher) Publisher() *Publisher {
+	return p
+}
+
+// Supervisor establece la conexión NATS en segundo plano, reintentando con
+// backoff exponencial hasta lograrlo, y expone el publisher una vez conectado.
+// Las caídas posteriores las maneja la reconexión automática del cliente NATS.
+type Supervisor struct {
+	conn   *Connection
+	logger *slog.Logger
+
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+
+	publisherOptions []PublisherOption
+	onConnect        []func(*nats.Conn)
+
+	publisher atomic.Pointer[Publisher]
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// NewSupervisor crea un supervisor para la conexión indicada.
+func NewSupervisor(conn *Connection, logger *slog.Logger) *Supervisor {
+	return &Supervisor{
+		conn:           conn,
+		logger:         logger,
+		initialBackoff: 500 * time.Millisecond,
+		maxBackoff:     30 * time.Second,
+	}
+}
+
+// WithPublisherOptions agrega opciones al publisher que se crea al conectar.
+func (s *Supervisor) WithPublisherOptions(opts ...PublisherOption) *Supervisor {
+	s.publisherOptions = append(s.publisherOptions, opts...)
+	return s
+}
+
+// OnConnect registra fn para ejecutarse una vez establecida la conexión, por
+// ejemplo para crear suscripciones. Debe llamarse antes de Start.
+func (s *Supervisor) OnConnect(fn func(*nats.Conn)) {
+	s.onConnect = append(s.onConnect, fn)
+}
+
+// Start inicia el ciclo de conexión en segundo plano y retorna de inmediato.
+func (s *Supervisor) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.wg.Add(1)
+	go s.run(ctx)
+}
+
+func (s *Supervisor) run(ctx context.Context) {
+	defer s.wg.Done()
+
+	backoff := s.initialBackoff
+	for attempt := 1; ; attempt++ {
+		publisher, err := s.connect()
+		if err == nil {
+			s.publisher.Store(publisher)
+			for _, fn := range s.onConnect {
+				fn(s.conn.GetConn())
+			}
+			return
+		}
+
+		s.logger.Warn("No se pudo conectar a NATS; reintentando",
+			"intento", attempt, "espera", backoff, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > s.maxBackoff {
+			backoff = s.maxBackoff
+		}
+	}
+}
+
+func (s *Supervisor) connect() (*Publisher, error) {
+	if err := s.conn.Connect(); err != nil {
+		return nil, err
+	}
+	publisher, err := NewPublisher(s.conn, s.publisherOptions...)
+	if err != nil {
+		s.conn.Close()
+		return nil, err
+	}
+	return publisher, nil
+}
+
+// Publisher retorna el publisher vigente, o nil si aún no hay conexión.
+func (s *Supervisor) Publisher() *Publisher {
+	return s.publisher.Load()
+}
+
+// Ready indica si hay un publisher disponible y la conexión está activa.
+func (s *Supervisor) Ready() bool {
+	return s.Publisher() != nil && s.conn.IsConnected()
+}
+
+// Stop detiene los reintentos y cierra el publisher y la conexión.
+func (s *Supervisor) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	if publisher := s.Publisher(); publisher != nil {
+		publisher.Close()
+	}
+	s.conn.Close()
+}
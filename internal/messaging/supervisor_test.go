@@ -0,0 +1,135 @@
+package messaging
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// dialerQueFalla retorna un Dialer que falla las primeras n veces y luego usa nats.Connect.
+func dialerQueFalla(n int32, intentos *atomic.Int32) Dialer {
+	return func(url string, options ...nats.Option) (*nats.Conn, error) {
+		if intentos.Add(1) <= n {
+			return nil, errors.New("servidor no disponible")
+		}
+		return nats.Connect(url, options...)
+	}
+}
+
+func nuevoSupervisorDePrueba(conn *Connection) *Supervisor {
+	s := NewSupervisor(conn, testLogger)
+	s.initialBackoff = 5 * time.Millisecond
+	s.maxBackoff = 20 * time.Millisecond
+	return s
+}
+
+func esperar(t *testing.T, condicion func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !condicion() {
+		if time.Now().After(deadline) {
+			t.Fatal("La condición no se cumplió a tiempo")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSupervisorConexionTardia(t *testing.T) {
+	srv := iniciarServidorNATS(t)
+
+	var intentos atomic.Int32
+	conn := NewConnection(srv.ClientURL(), testLogger)
+	conn.dial = dialerQueFalla(3, &intentos)
+
+	supervisor := nuevoSupervisorDePrueba(conn)
+	if supervisor.Publisher() != nil || supervisor.Ready() {
+		t.Fatal("El supervisor no debe estar listo antes de iniciar")
+	}
+
+	supervisor.Start()
+	defer supervisor.Stop()
+
+	esperar(t, supervisor.Ready)
+
+	if got := intentos.Load(); got != 4 {
+		t.Errorf("Intentos de conexión = %d; esperado 4", got)
+	}
+	if supervisor.Publisher() == nil {
+		t.Error("El publisher debe estar disponible tras conectar")
+	}
+}
+
+func TestSupervisorOnConnect(t *testing.T) {
+	srv := iniciarServidorNATS(t)
+
+	var intentos atomic.Int32
+	conn := NewConnection(srv.ClientURL(), testLogger)
+	conn.dial = dialerQueFalla(2, &intentos)
+
+	var llamadas atomic.Int32
+	supervisor := nuevoSupervisorDePrueba(conn)
+	supervisor.OnConnect(func(nc *nats.Conn) {
+		if nc == nil || !nc.IsConnected() {
+			t.Error("OnConnect recibió una conexión inactiva")
+		}
+		llamadas.Add(1)
+	})
+	supervisor.Start()
+	defer supervisor.Stop()
+
+	esperar(t, func() bool { return llamadas.Load() == 1 })
+	if !supervisor.Ready() {
+		t.Error("El supervisor debe estar listo al ejecutar OnConnect")
+	}
+}
+
+func TestSupervisorStopSinConexion(t *testing.T) {
+	var intentos atomic.Int32
+	conn := NewConnection("nats://127.0.0.1:1", testLogger)
+	conn.dial = dialerQueFalla(1<<30, &intentos)
+
+	supervisor := nuevoSupervisorDePrueba(conn)
+	supervisor.Start()
+
+	esperar(t, func() bool { return intentos.Load() >= 2 })
+
+	done := make(chan struct{})
+	go func() {
+		supervisor.Stop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stop no retornó mientras se reintentaba la conexión")
+	}
+
+	if supervisor.Ready() {
+		t.Error("El supervisor no debe estar listo sin conexión")
+	}
+
+	// No debe haber más intentos después de Stop
+	despues := intentos.Load()
+	time.Sleep(50 * time.Millisecond)
+	if intentos.Load() != despues {
+		t.Error("El supervisor siguió reintentando después de Stop")
+	}
+}
+
+func TestSupervisorDegradadoTrasCaida(t *testing.T) {
+	srv := iniciarServidorNATS(t)
+	conn := NewConnection(srv.ClientURL(), testLogger)
+
+	supervisor := nuevoSupervisorDePrueba(conn)
+	supervisor.Start()
+	defer supervisor.Stop()
+
+	esperar(t, supervisor.Ready)
+
+	srv.Shutdown()
+	esperar(t, func() bool { return !supervisor.Ready() })
+}
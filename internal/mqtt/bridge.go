@@ -0,0 +1,211 @@
+// Package mqtt implementa el puente de ingesta MQTT para rastreadores IoT que
+// no pueden enviar HTTPS con firma HMAC.
+package mqtt
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/120m4n/GridFlow-Dynamics/internal/ingest"
+	"github.com/120m4n/GridFlow-Dynamics/pkg/domain"
+)
+
+// Options configura la conexión del puente al broker MQTT.
+//
+// La autenticación por dispositivo (usuario/contraseña o certificado de
+// cliente) y las ACL que restringen cada dispositivo a su propio topic se
+// configuran en el broker; Username, Password y TLSConfig autentican al puente.
+type Options struct {
+	URL       string
+	Topic     string
+	ClientID  string
+	Username  string
+	Password  string
+	TLSConfig *tls.Config
+}
+
+// Bridge se suscribe con QoS 1 al patrón de topics configurado y entrega cada
+// mensaje al mismo ingest.Service que HTTP y gRPC, con su validación y rate
+// limit por cuadrilla.
+//
+// La sesión es persistente y la confirmación manual: un mensaje solo se
+// confirma al publicarse o al descartarse por inválido o por rate limit. Si
+// NATS no está disponible el puente reintenta la publicación con backoff
+// hasta lograrla; si se detiene antes, el mensaje queda sin confirmar y el
+// broker lo reentrega al reconectar. Así no se pierden lecturas durante una
+// caída.
+//
+// Los mensajes se procesan fuera de la goroutine de red del cliente, hasta
+// maxInFlight a la vez, para que un reintento no detenga los pings ni los
+// demás mensajes. Por eso el orden de publicación en NATS no está
+// garantizado; cada evento conserva su timestamp.
+type Bridge struct {
+	client  paho.Client
+	topic   string
+	service *ingest.Service
+	logger  *slog.Logger
+
+	// ctx se cancela en Stop para cortar los reintentos en curso.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// inFlight limita los mensajes procesándose a la vez.
+	inFlight chan struct{}
+
+	retryBackoff    time.Duration
+	maxRetryBackoff time.Duration
+}
+
+// maxInFlight es el máximo de mensajes MQTT procesándose a la vez.
+const maxInFlight = 16
+
+// NewBridge crea el puente; la conexión se establece con Start.
+func NewBridge(opts Options, service *ingest.Service, logger *slog.Logger) *Bridge {
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &Bridge{
+		topic:           opts.Topic,
+		service:         service,
+		logger:          logger,
+		ctx:             ctx,
+		cancel:          cancel,
+		inFlight:        make(chan struct{}, maxInFlight),
+		retryBackoff:    100 * time.Millisecond,
+		maxRetryBackoff: 5 * time.Second,
+	}
+
+	clientOpts := paho.NewClientOptions().
+		AddBroker(opts.URL).
+		SetClientID(opts.ClientID).
+		SetUsername(opts.Username).
+		SetPassword(opts.Password).
+		SetCleanSession(false).
+		SetAutoAckDisabled(true).
+		SetOrderMatters(false).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(time.Second).
+		SetMaxReconnectInterval(30 * time.Second).
+		SetOnConnectHandler(b.onConnect).
+		SetConnectionLostHandler(func(_ paho.Client, err error) {
+			b.logger.Warn("Conexión MQTT perdida; reconectando", "error", err)
+		})
+	if opts.TLSConfig != nil {
+		clientOpts.SetTLSConfig(opts.TLSConfig)
+	}
+
+	b.client = paho.NewClient(clientOpts)
+	return b
+}
+
+// Start inicia la conexión en segundo plano y retorna de inmediato; los
+// reintentos usan backoff hasta 30 s entre intentos.
+func (b *Bridge) Start() {
+	b.client.Connect()
+}
+
+// Ready indica si el puente está conectado al broker.
+func (b *Bridge) Ready() bool {
+	return b.client.IsConnectionOpen()
+}
+
+// Stop corta los reintentos de publicación y cierra la conexión esperando
+// hasta el límite del contexto a que terminen los mensajes en curso.
+func (b *Bridge) Stop(ctx context.Context) error {
+	b.cancel()
+	quiesce := uint(250)
+	if deadline, ok := ctx.Deadline(); ok {
+		quiesce = uint(max(time.Until(deadline).Milliseconds(), 0))
+	}
+	b.client.Disconnect(quiesce)
+	return nil
+}
+
+// onConnect (re)crea la suscripción en cada conexión.
+func (b *Bridge) onConnect(client paho.Client) {
+	token := client.Subscribe(b.topic, 1, b.handle)
+	go func() {
+		token.Wait()
+		if err := token.Error(); err != nil {
+			b.logger.Error("Fallo al suscribir al topic MQTT", "topic", b.topic, "error", err)
+			return
+		}
+		b.logger.Info("Puente MQTT suscrito", "topic", b.topic)
+	}()
+}
+
+// handle corre en su propia goroutine (OrderMatters deshabilitado) y espera
+// un cupo de inFlight antes de procesar el mensaje.
+func (b *Bridge) handle(_ paho.Client, msg paho.Message) {
+	select {
+	case b.inFlight <- struct{}{}:
+		defer func() { <-b.inFlight }()
+	case <-b.ctx.Done():
+		// Detenido: sin confirmar, el broker lo reentrega al reconectar
+		return
+	}
+
+	logger := b.logger.With("topic", msg.Topic(), "dispositivo", dispositivo(msg.Topic()))
+
+	var mensaje domain.MensajeInventarioCuadrilla
+	if err := json.Unmarshal(msg.Payload(), &mensaje); err != nil {
+		logger.Warn("Mensaje MQTT descartado: JSON inválido", "error", err)
+		msg.Ack()
+		return
+	}
+
+	var validationErr *ingest.ValidationError
+	err := b.submit(&mensaje)
+	switch {
+	case errors.As(err, &validationErr):
+		logger.Warn("Mensaje MQTT descartado: payload inválido", "error", err)
+	case errors.Is(err, ingest.ErrRateLimited):
+		logger.Warn("Mensaje MQTT descartado: rate limit excedido", "grupo_trabajo", mensaje.GrupoTrabajo)
+	case errors.Is(err, context.Canceled):
+		logger.Warn("Mensaje MQTT sin confirmar; se reentregará al reconectar", "grupo_trabajo", mensaje.GrupoTrabajo)
+		return
+	case err != nil:
+		logger.Error("Mensaje MQTT descartado: fallo al publicar", "grupo_trabajo", mensaje.GrupoTrabajo, "error", err)
+	default:
+		logger.Debug("Mensaje MQTT publicado", "grupo_trabajo", mensaje.GrupoTrabajo)
+	}
+	msg.Ack()
+}
+
+// submit entrega el mensaje al servicio de ingesta, reintentando con backoff
+// exponencial mientras NATS no esté disponible. Solo retorna
+// context.Canceled si el puente se detiene antes de publicar.
+func (b *Bridge) submit(mensaje *domain.MensajeInventarioCuadrilla) error {
+	backoff := b.retryBackoff
+	for {
+		err := b.service.Submit(b.ctx, mensaje.GrupoTrabajo, mensaje)
+		if !ingest.Unavailable(err) {
+			return err
+		}
+		b.logger.Warn("NATS no disponible; reintentando mensaje MQTT",
+			"grupo_trabajo", mensaje.GrupoTrabajo, "espera", backoff, "error", err)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-b.ctx.Done():
+			timer.Stop()
+			return context.Canceled
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, b.maxRetryBackoff)
+	}
+}
+
+// dispositivo extrae el identificador del dispositivo: el último nivel del topic.
+func dispositivo(topic string) string {
+	if i := strings.LastIndex(topic, "/"); i >= 0 {
+		return topic[i+1:]
+	}
+	return topic
+}
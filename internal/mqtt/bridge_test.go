@@ -0,0 +1,345 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	mochi "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/nats-io/nats.go"
+
+	"github.com/120m4n/GridFlow-Dynamics/internal/ingest"
+	"github.com/120m4n/GridFlow-Dynamics/internal/messaging"
+	"github.com/120m4n/GridFlow-Dynamics/internal/messaging/natstest"
+	"github.com/120m4n/GridFlow-Dynamics/internal/ratelimit"
+	"github.com/120m4n/GridFlow-Dynamics/pkg/domain"
+)
+
+const (
+	subjectPrueba  = "inventario.cuadrilla"
+	clientIDPrueba = "puente-prueba"
+)
+
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// proveedorConmutable permite simular la caída y recuperación de NATS.
+type proveedorConmutable struct {
+	publisher atomic.Pointer[messaging.Publisher]
+}
+
+func (p *proveedorConmutable) Publisher() *messaging.Publisher {
+	return p.publisher.Load()
+}
+
+// bufferSeguro es un io.Writer que puede leerse mientras el puente escribe logs.
+type bufferSeguro struct {
+	mu  sync.Mutex
+	buf strings.Builder
+}
+
+func (b *bufferSeguro) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *bufferSeguro) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func puertoLibre(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("No se pudo reservar puerto: %v", err)
+	}
+	defer ln.Close()
+	return ln.Addr().String()
+}
+
+// iniciarBroker levanta un broker MQTT embebido en addr. La función retornada
+// lo detiene y puede llamarse antes de la limpieza del test.
+func iniciarBroker(t *testing.T, addr string) (*mochi.Server, func()) {
+	t.Helper()
+	broker := mochi.New(&mochi.Options{InlineClient: true, Logger: testLogger})
+	if err := broker.AddHook(new(auth.AllowHook), nil); err != nil {
+		t.Fatalf("Error al configurar broker: %v", err)
+	}
+	if err := broker.AddListener(listeners.NewTCP(listeners.Config{ID: "tcp", Address: addr})); err != nil {
+		t.Fatalf("Error al escuchar en %s: %v", addr, err)
+	}
+	if err := broker.Serve(); err != nil {
+		t.Fatalf("Error al iniciar broker: %v", err)
+	}
+	var once sync.Once
+	detener := func() { once.Do(func() { broker.Close() }) }
+	t.Cleanup(detener)
+	return broker, detener
+}
+
+func iniciarPuente(t *testing.T, addr string, publishers messaging.PublisherProvider) *Bridge {
+	t.Helper()
+	return iniciarPuenteConLimite(t, addr, publishers, 100)
+}
+
+func iniciarPuenteConLimite(t *testing.T, addr string, publishers messaging.PublisherProvider, limite int) *Bridge {
+	t.Helper()
+	return iniciarPuenteCon(t, addr, publishers, limite, testLogger)
+}
+
+func iniciarPuenteCon(t *testing.T, addr string, publishers messaging.PublisherProvider, limite int, logger *slog.Logger) *Bridge {
+	t.Helper()
+	service := ingest.NewService(publishers, ratelimit.New(limite, time.Minute)).WithSubject(subjectPrueba)
+	bridge := NewBridge(Options{
+		URL:      "tcp://" + addr,
+		Topic:    "gridflow/tracking/+",
+		ClientID: clientIDPrueba,
+	}, service, logger)
+	bridge.retryBackoff = 10 * time.Millisecond
+	bridge.Start()
+	t.Cleanup(func() { bridge.Stop(context.Background()) })
+
+	esperarSuscripcion(t, bridge)
+	return bridge
+}
+
+// esperarSuscripcion espera a que el puente esté conectado y su suscripción activa.
+func esperarSuscripcion(t *testing.T, bridge *Bridge) {
+	t.Helper()
+	esperar(t, bridge.Ready)
+	// La suscripción se crea de forma asíncrona en onConnect
+	time.Sleep(100 * time.Millisecond)
+}
+
+func esperar(t *testing.T, condicion func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condicion() {
+		if time.Now().After(deadline) {
+			t.Fatal("La condición no se cumplió a tiempo")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func payloadValido(grupo string) []byte {
+	body, _ := json.Marshal(domain.MensajeInventarioCuadrilla{
+		GrupoTrabajo:       grupo,
+		NombreEmpleado:     "Juan Perez",
+		Timestamp:          time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+		Coordenadas:        domain.Coordenadas{Latitud: 4.6097, Longitud: -74.0817},
+		CodigoODT:          "ODT-001",
+		Estado:             "trabajando",
+		PorcentajeProgreso: 50,
+		NivelBateria:       80,
+	})
+	return body
+}
+
+func recibirEvento(t *testing.T, sub *nats.Subscription) domain.EventoInventarioCuadrilla {
+	t.Helper()
+	msg, err := sub.NextMsg(5 * time.Second)
+	if err != nil {
+		t.Fatalf("No se recibió el evento en NATS: %v", err)
+	}
+	var evento domain.EventoInventarioCuadrilla
+	if err := json.Unmarshal(msg.Data, &evento); err != nil {
+		t.Fatalf("Evento no es JSON válido: %v", err)
+	}
+	return evento
+}
+
+func sinEventos(t *testing.T, sub *nats.Subscription) {
+	t.Helper()
+	if msg, err := sub.NextMsg(200 * time.Millisecond); !errors.Is(err, nats.ErrTimeout) {
+		t.Fatalf("No se esperaban eventos, se obtuvo %v (error %v)", msg, err)
+	}
+}
+
+func TestBridgePublicaMensajeValido(t *testing.T) {
+	addr := puertoLibre(t)
+	broker, _ := iniciarBroker(t, addr)
+	publisher, sub := natstest.Subscribed(t, subjectPrueba)
+	iniciarPuente(t, addr, publisher)
+
+	if err := broker.Publish("gridflow/tracking/dispositivo-1", payloadValido("G0/CUADRILLA_1"), false, 1); err != nil {
+		t.Fatalf("Error al publicar en MQTT: %v", err)
+	}
+
+	evento := recibirEvento(t, sub)
+	if evento.GrupoTrabajo != "G0/CUADRILLA_1" {
+		t.Errorf("GrupoTrabajo = %s; esperado G0/CUADRILLA_1", evento.GrupoTrabajo)
+	}
+	if evento.RecibidoEn.IsZero() {
+		t.Error("RecibidoEn debe estar definido")
+	}
+}
+
+func TestBridgeDescartaPayloadsInvalidos(t *testing.T) {
+	addr := puertoLibre(t)
+	broker, _ := iniciarBroker(t, addr)
+	publisher, sub := natstest.Subscribed(t, subjectPrueba)
+	iniciarPuente(t, addr, publisher)
+
+	invalidos := map[string][]byte{
+		"JSON malformado":  []byte(`{"grupoTrabajo": `),
+		"no es JSON":       []byte("hola"),
+		"falta grupo":      []byte(`{"nombreEmpleado":"Juan","codigoODT":"ODT-1","timestamp":"2024-01-15T10:30:00Z","estado":"trabajando"}`),
+		"estado inválido":  []byte(`{"grupoTrabajo":"G1","nombreEmpleado":"Juan","codigoODT":"ODT-1","timestamp":"2024-01-15T10:30:00Z","estado":"durmiendo"}`),
+		"batería excedida": []byte(`{"grupoTrabajo":"G1","nombreEmpleado":"Juan","codigoODT":"ODT-1","timestamp":"2024-01-15T10:30:00Z","estado":"trabajando","nivelBateria":150}`),
+	}
+	for nombre, payload := range invalidos {
+		if err := broker.Publish("gridflow/tracking/dispositivo-1", payload, false, 1); err != nil {
+			t.Fatalf("%s: error al publicar en MQTT: %v", nombre, err)
+		}
+	}
+	sinEventos(t, sub)
+
+	// Un mensaje válido posterior sigue fluyendo
+	if err := broker.Publish("gridflow/tracking/dispositivo-1", payloadValido("G0/CUADRILLA_2"), false, 1); err != nil {
+		t.Fatalf("Error al publicar en MQTT: %v", err)
+	}
+	if evento := recibirEvento(t, sub); evento.GrupoTrabajo != "G0/CUADRILLA_2" {
+		t.Errorf("GrupoTrabajo = %s; esperado G0/CUADRILLA_2", evento.GrupoTrabajo)
+	}
+}
+
+func TestBridgeReconectaTrasReinicioDelBroker(t *testing.T) {
+	addr := puertoLibre(t)
+	_, detener := iniciarBroker(t, addr)
+	publisher, sub := natstest.Subscribed(t, subjectPrueba)
+	bridge := iniciarPuente(t, addr, publisher)
+
+	detener()
+	esperar(t, func() bool { return !bridge.Ready() })
+
+	broker, _ := iniciarBroker(t, addr)
+	esperarSuscripcion(t, bridge)
+
+	if err := broker.Publish("gridflow/tracking/dispositivo-1", payloadValido("G0/CUADRILLA_3"), false, 1); err != nil {
+		t.Fatalf("Error al publicar en MQTT: %v", err)
+	}
+	if evento := recibirEvento(t, sub); evento.GrupoTrabajo != "G0/CUADRILLA_3" {
+		t.Errorf("GrupoTrabajo = %s; esperado G0/CUADRILLA_3", evento.GrupoTrabajo)
+	}
+}
+
+func TestBridgeReentregaSinNATS(t *testing.T) {
+	addr := puertoLibre(t)
+	broker, _ := iniciarBroker(t, addr)
+	publisher, sub := natstest.Subscribed(t, subjectPrueba)
+
+	// NATS aún no disponible: el mensaje queda sin confirmar
+	proveedor := &proveedorConmutable{}
+	iniciarPuente(t, addr, proveedor)
+
+	if err := broker.Publish("gridflow/tracking/dispositivo-1", payloadValido("G0/CUADRILLA_4"), false, 1); err != nil {
+		t.Fatalf("Error al publicar en MQTT: %v", err)
+	}
+	sinEventos(t, sub)
+
+	// Al recuperarse NATS el reintento publica el mensaje sin reconectar a
+	// MQTT, y los mensajes siguientes no quedan bloqueados
+	proveedor.publisher.Store(publisher)
+	if evento := recibirEvento(t, sub); evento.GrupoTrabajo != "G0/CUADRILLA_4" {
+		t.Errorf("GrupoTrabajo = %s; esperado G0/CUADRILLA_4", evento.GrupoTrabajo)
+	}
+	if err := broker.Publish("gridflow/tracking/dispositivo-1", payloadValido("G0/CUADRILLA_5"), false, 1); err != nil {
+		t.Fatalf("Error al publicar en MQTT: %v", err)
+	}
+	if evento := recibirEvento(t, sub); evento.GrupoTrabajo != "G0/CUADRILLA_5" {
+		t.Errorf("GrupoTrabajo = %s; esperado G0/CUADRILLA_5", evento.GrupoTrabajo)
+	}
+}
+
+func TestBridgeProcesaMensajesDuranteReintentos(t *testing.T) {
+	addr := puertoLibre(t)
+	broker, _ := iniciarBroker(t, addr)
+	logs := &bufferSeguro{}
+	iniciarPuenteCon(t, addr, &proveedorConmutable{}, 100, slog.New(slog.NewTextHandler(logs, nil)))
+
+	// Sin NATS el primer mensaje queda reintentando
+	if err := broker.Publish("gridflow/tracking/dispositivo-1", payloadValido("G0/CUADRILLA_8"), false, 1); err != nil {
+		t.Fatalf("Error al publicar en MQTT: %v", err)
+	}
+	esperar(t, func() bool { return strings.Contains(logs.String(), "NATS no disponible") })
+
+	// El reintento no bloquea al cliente: el siguiente mensaje se procesa
+	if err := broker.Publish("gridflow/tracking/dispositivo-2", []byte("hola"), false, 1); err != nil {
+		t.Fatalf("Error al publicar en MQTT: %v", err)
+	}
+	esperar(t, func() bool { return strings.Contains(logs.String(), "JSON inválido") })
+}
+
+func TestBridgeReintentosNoAgotanRateLimit(t *testing.T) {
+	addr := puertoLibre(t)
+	broker, _ := iniciarBroker(t, addr)
+	publisher, sub := natstest.Subscribed(t, subjectPrueba)
+
+	// Con el circuito abierto cada reintento pasa el límite y falla al publicar
+	breaker := messaging.NewBreaker(1, time.Minute)
+	breaker.Record(subjectPrueba, errors.New("fallo previo"))
+	proveedor := &proveedorConmutable{}
+	proveedor.publisher.Store(natstest.Publisher(t, messaging.WithBreaker(breaker)))
+	logs := &bufferSeguro{}
+	iniciarPuenteCon(t, addr, proveedor, 1, slog.New(slog.NewTextHandler(logs, nil)))
+
+	if err := broker.Publish("gridflow/tracking/dispositivo-1", payloadValido("G0/CUADRILLA_9"), false, 1); err != nil {
+		t.Fatalf("Error al publicar en MQTT: %v", err)
+	}
+	// Más reintentos que el límite de la cuadrilla
+	esperar(t, func() bool { return strings.Count(logs.String(), "NATS no disponible") >= 3 })
+
+	proveedor.publisher.Store(publisher)
+	if evento := recibirEvento(t, sub); evento.GrupoTrabajo != "G0/CUADRILLA_9" {
+		t.Errorf("GrupoTrabajo = %s; esperado G0/CUADRILLA_9", evento.GrupoTrabajo)
+	}
+}
+
+func TestBridgeAplicaRateLimit(t *testing.T) {
+	addr := puertoLibre(t)
+	broker, _ := iniciarBroker(t, addr)
+	publisher, sub := natstest.Subscribed(t, subjectPrueba)
+	iniciarPuenteConLimite(t, addr, publisher, 1)
+
+	for i := 0; i < 2; i++ {
+		if err := broker.Publish("gridflow/tracking/dispositivo-1", payloadValido("G0/CUADRILLA_6"), false, 1); err != nil {
+			t.Fatalf("Error al publicar en MQTT: %v", err)
+		}
+	}
+	recibirEvento(t, sub)
+	sinEventos(t, sub)
+
+	// El límite es por cuadrilla
+	if err := broker.Publish("gridflow/tracking/dispositivo-2", payloadValido("G0/CUADRILLA_7"), false, 1); err != nil {
+		t.Fatalf("Error al publicar en MQTT: %v", err)
+	}
+	if evento := recibirEvento(t, sub); evento.GrupoTrabajo != "G0/CUADRILLA_7" {
+		t.Errorf("GrupoTrabajo = %s; esperado G0/CUADRILLA_7", evento.GrupoTrabajo)
+	}
+}
+
+func TestDispositivo(t *testing.T) {
+	tests := map[string]string{
+		"gridflow/tracking/dispositivo-1": "dispositivo-1",
+		"dispositivo-2":                   "dispositivo-2",
+		"gridflow/tracking/":              "",
+	}
+	for topic, esperado := range tests {
+		if got := dispositivo(topic); got != esperado {
+			t.Errorf("dispositivo(%q) = %q; esperado %q", topic, got, esperado)
+		}
+	}
+}
@@ -1,24 +1,25 @@
-// Package middleware provides HTTP middleware for the API.
-package middleware
+// Package ratelimit limits requests per crew. It is shared by the HTTP, gRPC
+// and MQTT ingestion paths and has no transport dependencies.
+package ratelimit
 
 import (
 	"sync"
 	"time"
 )
 
-// RateLimiter implements a sliding window rate limiter per crew.
-type RateLimiter struct {
+// Limiter implements a sliding window rate limiter per crew.
+type Limiter struct {
 	requests map[string][]time.Time
 	limit    int
 	window   time.Duration
 	mu       sync.RWMutex
 }
 
-// NewRateLimiter creates a new rate limiter.
+// New creates a new rate limiter.
 // limit: maximum requests allowed in the window
 // window: time window duration
-func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
-	rl := &RateLimiter{
+func New(limit int, window time.Duration) *Limiter {
+	rl := &Limiter{
 		requests: make(map[string][]time.Time),
 		limit:    limit,
 		window:   window,
@@ -29,7 +30,7 @@ func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
 }
 
 // Allow checks if a request from the given key is allowed.
-func (rl *RateLimiter) Allow(key string) bool {
+func (rl *Limiter) Allow(key string) bool {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
@@ -63,8 +64,37 @@ func (rl *RateLimiter) Allow(key string) bool {
 	return true
 }
 
+// Refund returns the most recent request recorded for key to its quota. It is
+// meant for requests that were allowed but could not be served, so that the
+// client's retry is not charged twice.
+func (rl *Limiter) Refund(key string) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	requests := rl.requests[key]
+	if len(requests) == 0 {
+		return
+	}
+	rl.requests[key] = requests[:len(requests)-1]
+}
+
+// SetLimit changes the maximum requests allowed per window. It applies from
+// the next Allow call; requests already recorded in the window still count.
+func (rl *Limiter) SetLimit(limit int) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.limit = limit
+}
+
+// Limit returns the maximum requests allowed per window.
+func (rl *Limiter) Limit() int {
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+	return rl.limit
+}
+
 // cleanup periodically removes old entries to prevent memory leaks.
-func (rl *RateLimiter) cleanup() {
+func (rl *Limiter) cleanup() {
 	ticker := time.NewTicker(rl.window * 2)
 	for range ticker.C {
 		rl.mu.Lock()
@@ -87,8 +117,10 @@ func (rl *RateLimiter) cleanup() {
 	}
 }
 
-// Remaining returns the number of remaining requests for a key.
-func (rl *RateLimiter) Remaining(key string) int {
+// Remaining returns the number of remaining requests for a key. It is never
+// negative, even after SetLimit lowers the limit below the requests already
+// recorded in the window.
+func (rl *Limiter) Remaining(key string) int {
 	rl.mu.RLock()
 	defer rl.mu.RUnlock()
 
@@ -107,5 +139,5 @@ func (rl *RateLimiter) Remaining(key string) int {
 		}
 	}
 
-	return rl.limit - count
+	return max(rl.limit-count, 0)
 }
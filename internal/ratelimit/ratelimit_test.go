@@ -1,14 +1,14 @@
-package middleware
+package ratelimit
 
 import (
 	"testing"
 	"time"
 )
 
-func TestNewRateLimiter(t *testing.T) {
-	rl := NewRateLimiter(100, time.Minute)
+func TestNew(t *testing.T) {
+	rl := New(100, time.Minute)
 	if rl == nil {
-		t.Fatal("NewRateLimiter returned nil")
+		t.Fatal("New returned nil")
 	}
 	if rl.limit != 100 {
 		t.Errorf("limit = %d; want 100", rl.limit)
@@ -18,8 +18,8 @@ func TestNewRateLimiter(t *testing.T) {
 	}
 }
 
-func TestRateLimiterAllow(t *testing.T) {
-	rl := NewRateLimiter(3, time.Second)
+func TestLimiterAllow(t *testing.T) {
+	rl := New(3, time.Second)
 
 	// First 3 requests should be allowed
 	for i := 0; i < 3; i++ {
@@ -34,8 +34,8 @@ func TestRateLimiterAllow(t *testing.T) {
 	}
 }
 
-func TestRateLimiterAllowDifferentKeys(t *testing.T) {
-	rl := NewRateLimiter(2, time.Second)
+func TestLimiterAllowDifferentKeys(t *testing.T) {
+	rl := New(2, time.Second)
 
 	// Both crews should have independent limits
 	if !rl.Allow("crew-001") {
@@ -60,8 +60,8 @@ func TestRateLimiterAllowDifferentKeys(t *testing.T) {
 	}
 }
 
-func TestRateLimiterRemaining(t *testing.T) {
-	rl := NewRateLimiter(5, time.Second)
+func TestLimiterRemaining(t *testing.T) {
+	rl := New(5, time.Second)
 
 	// Initial remaining should be limit
 	if remaining := rl.Remaining("crew-001"); remaining != 5 {
@@ -77,8 +77,8 @@ func TestRateLimiterRemaining(t *testing.T) {
 	}
 }
 
-func TestRateLimiterWindowExpiry(t *testing.T) {
-	rl := NewRateLimiter(2, 100*time.Millisecond)
+func TestLimiterWindowExpiry(t *testing.T) {
+	rl := New(2, 100*time.Millisecond)
 
 	// Use up the limit
 	rl.Allow("crew-001")
@@ -98,8 +98,56 @@ func TestRateLimiterWindowExpiry(t *testing.T) {
 	}
 }
 
-func TestRateLimiterConcurrent(t *testing.T) {
-	rl := NewRateLimiter(100, time.Minute)
+func TestLimiterRefund(t *testing.T) {
+	rl := New(1, time.Minute)
+
+	// Refunding an unknown key is a no-op
+	rl.Refund("crew-001")
+
+	rl.Allow("crew-001")
+	rl.Refund("crew-001")
+	if !rl.Allow("crew-001") {
+		t.Error("Request should be allowed after a refund")
+	}
+	if rl.Allow("crew-001") {
+		t.Error("2nd request should be denied once the refunded quota is used")
+	}
+}
+
+func TestLimiterSetLimit(t *testing.T) {
+	rl := New(2, time.Minute)
+
+	rl.Allow("crew-001")
+	rl.Allow("crew-001")
+	if rl.Allow("crew-001") {
+		t.Error("3rd request should be denied with limit 2")
+	}
+
+	rl.SetLimit(4)
+	if rl.Limit() != 4 {
+		t.Errorf("Limit = %d; want 4", rl.Limit())
+	}
+
+	// Raised limit takes effect on the next Allow call
+	if !rl.Allow("crew-001") {
+		t.Error("Request should be allowed after raising the limit")
+	}
+	if remaining := rl.Remaining("crew-001"); remaining != 1 {
+		t.Errorf("Remaining = %d; want 1", remaining)
+	}
+
+	// Lowered limit denies immediately
+	rl.SetLimit(1)
+	if rl.Allow("crew-001") {
+		t.Error("Request should be denied after lowering the limit")
+	}
+	if remaining := rl.Remaining("crew-001"); remaining != 0 {
+		t.Errorf("Remaining = %d; want 0 when the window already exceeds the lowered limit", remaining)
+	}
+}
+
+func TestLimiterConcurrent(t *testing.T) {
+	rl := New(100, time.Minute)
 
 	done := make(chan bool)
 	for i := 0; i < 10; i++ {
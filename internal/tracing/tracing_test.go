@@ -0,0 +1,51 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected bool
+	}{
+		{name: "no endpoint", env: map[string]string{}, expected: false},
+		{name: "generic endpoint", env: map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318"}, expected: true},
+		{name: "traces endpoint", env: map[string]string{"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "http://collector:4318/v1/traces"}, expected: true},
+		{
+			name:     "sdk disabled",
+			env:      map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318", "OTEL_SDK_DISABLED": "true"},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+			t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+			t.Setenv("OTEL_SDK_DISABLED", "")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			if got := Enabled(); got != tt.expected {
+				t.Errorf("Enabled() = %v; want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSetupNoopWithoutEndpoint(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+
+	shutdown, err := Setup(context.Background(), "gridflow-test")
+	if err != nil {
+		t.Fatalf("Setup error: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown error: %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+// Package version exposes build metadata injected at link time.
+//
+// Build with:
+//
+//	go build -ldflags "-X github.com/120m4n/GridFlow-Dynamics/internal/version.Version=v1.2.3 \
+//	  -X github.com/120m4n/GridFlow-Dynamics/internal/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X github.com/120m4n/GridFlow-Dynamics/internal/version.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+package version
+
+import (
+	"runtime"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Values overridden via -ldflags; "dev" marks a local build.
+var (
+	Version   = "dev"
+	Commit    = "dev"
+	BuildDate = "dev"
+)
+
+var startTime = time.Now()
+
+// Info describes the running build and process.
+type Info struct {
+	Version   string    `json:"version"`
+	Commit    string    `json:"commit"`
+	BuildDate string    `json:"build_date"`
+	GoVersion string    `json:"go_version"`
+	StartTime time.Time `json:"start_time"`
+	Uptime    string    `json:"uptime"`
+}
+
+// Get returns the build metadata together with the process start time and uptime.
+func Get() Info {
+	return Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+		StartTime: startTime.UTC(),
+		Uptime:    time.Since(startTime).Round(time.Second).String(),
+	}
+}
+
+// LogAttrs returns the build metadata as key/value pairs for slog.
+func LogAttrs() []any {
+	return []any{"version", Version, "commit", Commit, "build_date", BuildDate, "go_version", runtime.Version()}
+}
+
+// NewBuildInfoCollector returns a gauge fixed at 1 whose labels describe the build.
+func NewBuildInfoCollector() prometheus.Collector {
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gridflow_build_info",
+		Help: "Build metadata of the running binary; the value is always 1.",
+	}, []string{"version", "commit", "build_date", "go_version"})
+	gauge.WithLabelValues(Version, Commit, BuildDate, runtime.Version()).Set(1)
+	return gauge
+}
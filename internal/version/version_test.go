@@ -0,0 +1,36 @@
+package version
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestGetDefaults(t *testing.T) {
+	info := Get()
+	if info.Version != "dev" || info.Commit != "dev" || info.BuildDate != "dev" {
+		t.Errorf("expected dev defaults, got %+v", info)
+	}
+	if info.GoVersion != runtime.Version() {
+		t.Errorf("expected Go version %s, got %s", runtime.Version(), info.GoVersion)
+	}
+	if info.StartTime.IsZero() {
+		t.Error("expected start time to be set")
+	}
+	if info.Uptime == "" {
+		t.Error("expected uptime to be set")
+	}
+}
+
+func TestBuildInfoCollector(t *testing.T) {
+	expected := `
+# HELP gridflow_build_info Build metadata of the running binary; the value is always 1.
+# TYPE gridflow_build_info gauge
+gridflow_build_info{build_date="dev",commit="dev",go_version="` + runtime.Version() + `",version="dev"} 1
+`
+	if err := testutil.CollectAndCompare(NewBuildInfoCollector(), strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
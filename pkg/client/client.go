@@ -0,0 +1,157 @@
+// Package client es el cliente Go de la API de inventario de cuadrillas.
+// Firma los mensajes con el mismo código que valida el servidor (pkg/domain) y reintenta
+// las respuestas 429 y 503 respetando Retry-After. Se versiona junto con el
+// servidor, de modo que el payload siempre coincide.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/120m4n/GridFlow-Dynamics/pkg/domain"
+)
+
+// InventarioPath es la ruta del endpoint de inventario de cuadrilla.
+const InventarioPath = "/api/v1/mensaje_inventario/cuadrilla"
+
+// Mensaje es el payload de inventario de cuadrilla que acepta la API.
+type Mensaje = domain.MensajeInventarioCuadrilla
+
+// Coordenadas es la ubicación GPS de un Mensaje.
+type Coordenadas = domain.Coordenadas
+
+const (
+	defaultRetries      = 3
+	defaultRetryWait    = time.Second
+	defaultMaxRetryWait = 30 * time.Second
+)
+
+// Error es el sobre de error retornado por la API.
+type Error struct {
+	StatusCode int    `json:"-"`
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	RequestID  string `json:"request_id,omitempty"`
+	Retryable  bool   `json:"retryable,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("gridflow: %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// Client envía mensajes de inventario firmados a la API.
+type Client struct {
+	baseURL      string
+	secretKey    []byte
+	httpClient   *http.Client
+	retries      int
+	maxRetryWait time.Duration
+}
+
+// Option configura un Client.
+type Option func(*Client)
+
+// WithHTTPClient usa httpClient para las solicitudes en lugar de http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetry configura cuántas veces se reintenta una respuesta 429 o 503 y
+// la espera máxima entre intentos, aunque Retry-After pida más. Con retries
+// en 0 no se reintenta.
+func WithRetry(retries int, maxWait time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.maxRetryWait = maxWait
+	}
+}
+
+// New crea un cliente para la API en baseURL (por ejemplo
+// "http://localhost:8080") que firma con secretKey.
+func New(baseURL, secretKey string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:      strings.TrimSuffix(baseURL, "/"),
+		secretKey:    []byte(secretKey),
+		httpClient:   http.DefaultClient,
+		retries:      defaultRetries,
+		maxRetryWait: defaultMaxRetryWait,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// SubmitInventario envía un mensaje de inventario de cuadrilla. Los rechazos
+// de la API se retornan como *Error.
+func (c *Client) SubmitInventario(ctx context.Context, m *Mensaje) error {
+	body, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("fallo al serializar el mensaje: %w", err)
+	}
+	signature := domain.ComputeSignature(c.secretKey, body)
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+InventarioPath, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("fallo al crear la solicitud: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(domain.SignatureHeader, signature)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("fallo al enviar el mensaje: %w", err)
+		}
+		if resp.StatusCode == http.StatusOK {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			return nil
+		}
+
+		apiErr := readError(resp)
+		if attempt >= c.retries || !retryable(resp.StatusCode) {
+			return apiErr
+		}
+
+		timer := time.NewTimer(c.retryWait(resp.Header.Get("Retry-After")))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w (último error: %v)", ctx.Err(), apiErr)
+		case <-timer.C:
+		}
+	}
+}
+
+// retryWait interpreta Retry-After en segundos, limitado a maxRetryWait.
+func (c *Client) retryWait(retryAfter string) time.Duration {
+	wait := defaultRetryWait
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+		wait = time.Duration(seconds) * time.Second
+	}
+	return min(wait, c.maxRetryWait)
+}
+
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable
+}
+
+// readError decodifica el sobre de error y cierra el cuerpo de resp.
+func readError(resp *http.Response) *Error {
+	defer resp.Body.Close()
+	apiErr := &Error{StatusCode: resp.StatusCode}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(apiErr); err != nil || apiErr.Code == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	return apiErr
+}
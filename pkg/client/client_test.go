@@ -0,0 +1,175 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+
+	"github.com/120m4n/GridFlow-Dynamics/internal/api/apierror"
+	"github.com/120m4n/GridFlow-Dynamics/internal/api/handlers"
+	"github.com/120m4n/GridFlow-Dynamics/internal/api/middleware"
+	"github.com/120m4n/GridFlow-Dynamics/internal/messaging"
+	"github.com/120m4n/GridFlow-Dynamics/internal/messaging/natstest"
+	"github.com/120m4n/GridFlow-Dynamics/internal/ratelimit"
+)
+
+const secreto = "test-secret"
+
+// iniciarAPI levanta el handler real de inventario detrás de un servidor
+// HTTP y cuenta las solicitudes recibidas. Sin publishers responde 503.
+func iniciarAPI(t *testing.T, publishers messaging.PublisherProvider) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	handler := handlers.NewInventarioHandler(publishers, ratelimit.New(100, time.Minute)).
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	var solicitudes atomic.Int32
+	app := fiber.New(fiber.Config{ErrorHandler: apierror.ErrorHandler})
+	app.Use(func(c *fiber.Ctx) error {
+		solicitudes.Add(1)
+		return c.Next()
+	})
+	app.Post(InventarioPath, middleware.RequireSignature(middleware.NewHMACValidator(secreto)), handler.Handle)
+
+	srv := httptest.NewServer(adaptor.FiberApp(app))
+	t.Cleanup(srv.Close)
+	return srv, &solicitudes
+}
+
+func mensajeValido() *Mensaje {
+	return &Mensaje{
+		GrupoTrabajo:       "G0/TEST",
+		NombreEmpleado:     "Juan Perez",
+		Timestamp:          time.Now(),
+		Coordenadas:        Coordenadas{Latitud: 40.0, Longitud: -74.0},
+		CodigoODT:          "ODT-001",
+		Estado:             "trabajando",
+		PorcentajeProgreso: 75,
+		NivelBateria:       85,
+	}
+}
+
+func TestSubmitInventario(t *testing.T) {
+	srv, _ := iniciarAPI(t, natstest.Publisher(t))
+
+	c := New(srv.URL+"/", secreto, WithHTTPClient(srv.Client()))
+	if err := c.SubmitInventario(context.Background(), mensajeValido()); err != nil {
+		t.Fatalf("SubmitInventario error: %v", err)
+	}
+}
+
+func TestSubmitInventarioErrores(t *testing.T) {
+	tests := []struct {
+		nombre          string
+		secreto         string
+		mensaje         func(*Mensaje)
+		sinNATS         bool
+		wantStatus      int
+		wantCode        apierror.Code
+		wantSolicitudes int32
+	}{
+		{
+			nombre:          "firma inválida",
+			secreto:         "otro-secreto",
+			wantStatus:      http.StatusUnauthorized,
+			wantCode:        apierror.CodeBadSignature,
+			wantSolicitudes: 1,
+		},
+		{
+			nombre:          "validación",
+			secreto:         secreto,
+			mensaje:         func(m *Mensaje) { m.Estado = "desconocido" },
+			wantStatus:      http.StatusBadRequest,
+			wantCode:        apierror.CodeValidation,
+			wantSolicitudes: 1,
+		},
+		{
+			// Sin conexión a NATS la API responde 503 y el cliente reintenta
+			nombre:          "no disponible",
+			secreto:         secreto,
+			sinNATS:         true,
+			wantStatus:      http.StatusServiceUnavailable,
+			wantCode:        apierror.CodeUnavailable,
+			wantSolicitudes: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			var publishers messaging.PublisherProvider
+			if !tt.sinNATS {
+				publishers = natstest.Publisher(t)
+			}
+			srv, solicitudes := iniciarAPI(t, publishers)
+
+			m := mensajeValido()
+			if tt.mensaje != nil {
+				tt.mensaje(m)
+			}
+			c := New(srv.URL, tt.secreto, WithHTTPClient(srv.Client()), WithRetry(2, time.Millisecond))
+			err := c.SubmitInventario(context.Background(), m)
+
+			var apiErr *Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error = %v; esperado *Error", err)
+			}
+			if apiErr.StatusCode != tt.wantStatus || apiErr.Code != string(tt.wantCode) {
+				t.Errorf("error = %d %s; esperado %d %s", apiErr.StatusCode, apiErr.Code, tt.wantStatus, tt.wantCode)
+			}
+			if n := solicitudes.Load(); n != tt.wantSolicitudes {
+				t.Errorf("solicitudes = %d; esperado %d", n, tt.wantSolicitudes)
+			}
+		})
+	}
+}
+
+func TestSubmitInventarioRespetaRetryAfter(t *testing.T) {
+	var solicitudes atomic.Int32
+	var primera, segunda time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if solicitudes.Add(1) == 1 {
+			primera = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		segunda = time.Now()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, secreto, WithHTTPClient(srv.Client()))
+	if err := c.SubmitInventario(context.Background(), mensajeValido()); err != nil {
+		t.Fatalf("SubmitInventario error: %v", err)
+	}
+	if n := solicitudes.Load(); n != 2 {
+		t.Fatalf("solicitudes = %d; esperado 2", n)
+	}
+	if espera := segunda.Sub(primera); espera < time.Second {
+		t.Errorf("espera entre intentos = %v; esperado al menos el Retry-After de 1s", espera)
+	}
+}
+
+func TestSubmitInventarioCancelado(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	c := New(srv.URL, secreto, WithHTTPClient(srv.Client()))
+	if err := c.SubmitInventario(ctx, mensajeValido()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v; esperado context.DeadlineExceeded", err)
+	}
+}
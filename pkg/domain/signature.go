@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// SignatureHeader es el header HTTP con la firma HMAC-SHA256 del cuerpo.
+const SignatureHeader = "X-Signature-256"
+
+// ComputeSignature retorna la firma HMAC-SHA256 de body con secret, en hex.
+// La firma cubre los bytes exactos enviados como cuerpo.
+func ComputeSignature(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "testing"
+
+func TestComputeSignature(t *testing.T) {
+	// Vector conocido de HMAC-SHA256
+	got := ComputeSignature([]byte("key"), []byte("The quick brown fox jumps over the lazy dog"))
+	want := "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"
+	if got != want {
+		t.Errorf("ComputeSignature = %s; esperado %s", got, want)
+	}
+}
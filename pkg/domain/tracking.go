@@ -1,3 +1,7 @@
+// Package domain define el contrato de la API de inventario de cuadrillas: el
+// payload recibido, el evento publicado a NATS y la firma de las solicitudes.
+// Solo depende de la biblioteca estándar, para que pkg/client lo comparta con
+// el servidor sin arrastrar sus dependencias.
 package domain
 
 import (
@@ -98,3 +102,18 @@ type EventoInventarioCuadrilla struct {
 	NivelBateria       int         `json:"nivel_bateria"`
 	RecibidoEn         time.Time   `json:"recibido_en"`
 }
+
+// Evento convierte el mensaje validado en el evento que se publica a NATS.
+func (m *MensajeInventarioCuadrilla) Evento(recibidoEn time.Time) *EventoInventarioCuadrilla {
+	return &EventoInventarioCuadrilla{
+		GrupoTrabajo:       m.GrupoTrabajo,
+		NombreEmpleado:     m.NombreEmpleado,
+		Timestamp:          m.Timestamp,
+		Coordenadas:        m.Coordenadas,
+		CodigoODT:          m.CodigoODT,
+		Estado:             m.Estado,
+		PorcentajeProgreso: m.PorcentajeProgreso,
+		NivelBateria:       m.NivelBateria,
+		RecibidoEn:         recibidoEn,
+	}
+}
@@ -170,3 +170,32 @@ func TestEventoInventarioCuadrilla(t *testing.T) {
 		t.Errorf("Estado = %s; esperado trabajando", evento.Estado)
 	}
 }
+
+func TestMensajeInventarioCuadrillaEvento(t *testing.T) {
+	mensaje := MensajeInventarioCuadrilla{
+		GrupoTrabajo:       "G0/CUADRILLA_123",
+		NombreEmpleado:     "Juan Perez",
+		Timestamp:          time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+		Coordenadas:        Coordenadas{Latitud: 4.6097, Longitud: -74.0817},
+		CodigoODT:          "ODT-001",
+		Estado:             "trabajando",
+		PorcentajeProgreso: 75,
+		NivelBateria:       85,
+	}
+	recibido := time.Date(2024, 1, 15, 10, 30, 5, 0, time.UTC)
+
+	evento := mensaje.Evento(recibido)
+
+	if evento.GrupoTrabajo != mensaje.GrupoTrabajo || evento.CodigoODT != mensaje.CodigoODT {
+		t.Errorf("Identificadores no copiados: %+v", evento)
+	}
+	if evento.Coordenadas != mensaje.Coordenadas || !evento.Timestamp.Equal(mensaje.Timestamp) {
+		t.Errorf("Ubicación o timestamp no copiados: %+v", evento)
+	}
+	if evento.PorcentajeProgreso != 75 || evento.NivelBateria != 85 || evento.Estado != "trabajando" {
+		t.Errorf("Estado no copiado: %+v", evento)
+	}
+	if !evento.RecibidoEn.Equal(recibido) {
+		t.Errorf("RecibidoEn = %v; esperado %v", evento.RecibidoEn, recibido)
+	}
+}